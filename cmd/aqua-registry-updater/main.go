@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -35,15 +38,35 @@ func core() int {
 		logger.Error("GITHUB_REPOSITORY should include /")
 		return 1
 	}
-	gh, err := controller.NewGitHub(ctx, token)
+	urls, err := controller.NewGitHubURLs(os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_BASE_URL"), os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_UPLOAD_URL"))
+	if err != nil {
+		slogerr.WithError(logger.Logger, err).Error("get GitHub URLs")
+		return 1
+	}
+	app, err := getGitHubApp(urls)
+	if err != nil {
+		slogerr.WithError(logger.Logger, err).Error("read the configuration of the GitHub App")
+		return 1
+	}
+	ts, err := controller.NewTokenSource(ctx, token, app)
+	if err != nil {
+		slogerr.WithError(logger.Logger, err).Error("create a token source")
+		return 1
+	}
+	rateLimit := controller.NewRateLimit()
+	httpClient := controller.NewHTTPClient(ctx, ts, rateLimit)
+	gh, err := controller.NewGitHub(httpClient, urls)
 	if err != nil {
 		slogerr.WithError(logger.Logger, err).Error("create a GitHub client")
 		return 1
 	}
 	ctrl := controller.New(afero.NewOsFs(), &controller.ParamNew{
-		RepoOwner: repoOwner,
-		RepoName:  repoName,
-	}, gh.PullRequests)
+		RepoOwner:   repoOwner,
+		RepoName:    repoName,
+		RateLimit:   rateLimit,
+		URLs:        urls,
+		TokenSource: ts,
+	}, gh.PullRequests, gh.Issues, gh.Git, controller.NewGraphQL(httpClient, urls.GraphQLURL), gh.Repositories, gh.RateLimit)
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 	if err := ctrl.Update(ctx, logger.Logger, &controller.Param{
@@ -55,3 +78,30 @@ func core() int {
 	}
 	return 0
 }
+
+// getGitHubApp reads the configuration of the GitHub App from environment variables.
+// If the GitHub App isn't configured, it returns nil.
+func getGitHubApp(urls *controller.GitHubURLs) (*controller.ParamGitHubApp, error) {
+	appID := os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_APP_ID")
+	if appID == "" {
+		return nil, nil //nolint:nilnil
+	}
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse AQUA_REGISTRY_UPDATER_GITHUB_APP_ID as an integer: %w", err)
+	}
+	installationID, err := strconv.ParseInt(os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_APP_INSTALLATION_ID"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse AQUA_REGISTRY_UPDATER_GITHUB_APP_INSTALLATION_ID as an integer: %w", err)
+	}
+	keyFile := os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_APP_PRIVATE_KEY_FILE")
+	if keyFile == "" {
+		return nil, errors.New("AQUA_REGISTRY_UPDATER_GITHUB_APP_PRIVATE_KEY_FILE is required")
+	}
+	return &controller.ParamGitHubApp{
+		AppID:          id,
+		InstallationID: installationID,
+		PrivateKeyFile: keyFile,
+		URLs:           urls,
+	}, nil
+}
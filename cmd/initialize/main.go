@@ -34,7 +34,19 @@ func core() int {
 		logger.Error("GITHUB_REPOSITORY should include /")
 		return 1
 	}
-	gh, err := controller.NewGitHub(ctx, token)
+	ts, err := controller.NewTokenSource(ctx, token, nil)
+	if err != nil {
+		slogerr.WithError(logger.Logger, err).Error("create a token source")
+		return 1
+	}
+	urls, err := controller.NewGitHubURLs(os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_BASE_URL"), os.Getenv("AQUA_REGISTRY_UPDATER_GITHUB_UPLOAD_URL"))
+	if err != nil {
+		slogerr.WithError(logger.Logger, err).Error("get GitHub URLs")
+		return 1
+	}
+	rateLimit := controller.NewRateLimit()
+	httpClient := controller.NewHTTPClient(ctx, ts, rateLimit)
+	gh, err := controller.NewGitHub(httpClient, urls)
 	if err != nil {
 		slogerr.WithError(logger.Logger, err).Error("create a GitHub client")
 		return 1
@@ -42,7 +54,9 @@ func core() int {
 	ctrl := controller.New(afero.NewOsFs(), &controller.ParamNew{
 		RepoOwner: repoOwner,
 		RepoName:  repoName,
-	}, gh.PullRequests)
+		RateLimit: rateLimit,
+		URLs:      urls,
+	}, gh.PullRequests, gh.Issues, gh.Git, controller.NewGraphQL(httpClient, urls.GraphQLURL), gh.Repositories, gh.RateLimit)
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 	if err := ctrl.Init(ctx, logger.Logger, &controller.Param{
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// addAliasToPackage adds alias to aliases of the package's registry.yaml.
+// It returns false if the alias already exists.
+func (c *Controller) addAliasToPackage(pkgName, alias string) (bool, error) {
+	registryPath := filepath.Join("pkgs", filepath.FromSlash(pkgName), "registry.yaml")
+	stat, err := c.fs.Stat(registryPath)
+	if err != nil {
+		return false, fmt.Errorf("stat registry.yaml: %w", err)
+	}
+	body, err := afero.ReadFile(c.fs, registryPath)
+	if err != nil {
+		return false, fmt.Errorf("read registry.yaml: %w", err)
+	}
+	b, added, err := addAlias(body, alias)
+	if err != nil {
+		return false, fmt.Errorf("add an alias to registry.yaml: %w", err)
+	}
+	if !added {
+		return false, nil
+	}
+	if err := afero.WriteFile(c.fs, registryPath, b, stat.Mode()); err != nil {
+		return false, fmt.Errorf("write registry.yaml: %w", err)
+	}
+	return true, nil
+}
+
+// addAlias adds alias to aliases of the package in registry.yaml.
+// registry.yaml is edited as a YAML node to keep comments.
+// aliases is added after repo_name or name if it doesn't exist.
+func addAlias(body []byte, alias string) ([]byte, bool, error) {
+	doc, pkg, err := parseRegistryNode(body)
+	if err != nil {
+		return nil, false, err
+	}
+	aliasNode := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "name"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: alias},
+		},
+	}
+	if aliases := mappingValue(pkg, "aliases"); aliases != nil {
+		if aliases.Kind != yaml.SequenceNode {
+			return nil, false, errors.New("aliases must be a list")
+		}
+		for _, a := range aliases.Content {
+			if name := mappingValue(a, "name"); name != nil && name.Value == alias {
+				return body, false, nil
+			}
+		}
+		aliases.Content = append(aliases.Content, aliasNode)
+	} else {
+		idx := len(pkg.Content)
+		for i := 0; i+1 < len(pkg.Content); i += 2 {
+			if key := pkg.Content[i].Value; key == "name" || key == "repo_name" {
+				idx = i + 2
+			}
+		}
+		pkg.Content = append(pkg.Content[:idx], append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "aliases"},
+			{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{aliasNode}},
+		}, pkg.Content[idx:]...)...)
+	}
+	b, err := encodeYAMLNode(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
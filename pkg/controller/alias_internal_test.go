@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"testing"
+)
+
+func Test_addAlias(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name  string
+		body  string
+		alias string
+		exp   string
+		added bool
+	}{
+		{
+			name: "add aliases",
+			body: `# yaml-language-server: $schema=https://raw.githubusercontent.com/aquaproj/aqua/main/json-schema/registry.json
+packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    # asset of the package
+    asset: foo_{{.OS}}.tar.gz
+`,
+			alias: "suzuki-shunsuke/foo",
+			added: true,
+			exp: `# yaml-language-server: $schema=https://raw.githubusercontent.com/aquaproj/aqua/main/json-schema/registry.json
+packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    aliases:
+      - name: suzuki-shunsuke/foo
+    # asset of the package
+    asset: foo_{{.OS}}.tar.gz
+`,
+		},
+		{
+			name: "append to aliases",
+			body: `packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    aliases:
+      - name: baz/foo
+`,
+			alias: "suzuki-shunsuke/foo",
+			added: true,
+			exp: `packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    aliases:
+      - name: baz/foo
+      - name: suzuki-shunsuke/foo
+`,
+		},
+		{
+			name: "alias exists",
+			body: `packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    aliases:
+      - name: suzuki-shunsuke/foo
+`,
+			alias: "suzuki-shunsuke/foo",
+			exp: `packages:
+  - type: github_release
+    repo_owner: bar
+    repo_name: foo
+    aliases:
+      - name: suzuki-shunsuke/foo
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			b, added, err := addAlias([]byte(d.body), d.alias)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if added != d.added {
+				t.Fatalf("wanted %v, got %v", d.added, added)
+			}
+			if string(b) != d.exp {
+				t.Fatalf("wanted:\n%s\ngot:\n%s", d.exp, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	genrg "github.com/aquaproj/registry-tool/pkg/generate-registry"
+)
+
+// ArchivedConfig is the configuration to propose removing packages whose GitHub repositories were archived.
+type ArchivedConfig struct {
+	Enabled bool
+}
+
+func (a *ArchivedConfig) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// handleArchived creates a pull request to remove the package if the package's GitHub repository was archived.
+// The pull request number is stored in data.json, so the pull request isn't created again after it's closed.
+// It returns true if the pull request is open or created, which means the package is skipped.
+func (c *Controller) handleArchived(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, state *runState, upstream *upstreamRepository) (f bool, e error) {
+	if !upstream.repo.GetArchived() {
+		if pkg.ArchivedPRNumber != 0 {
+			logger.Info("the package's repository was unarchived", "pr_number", pkg.ArchivedPRNumber)
+			pkg.ArchivedPRNumber = 0
+		}
+		return false, nil
+	}
+	pkgInfo := upstream.pkgInfo
+	logger.Info("the package's repository was archived", "repo_owner", pkgInfo.RepoOwner, "repo_name", pkgInfo.RepoName)
+	branch := branchPrefix + "archived-" + pkg.Name
+	if pr := state.findPR(branch); pr != nil {
+		logger.Info("skip the archived repository because the pull request already exists", "pr_number", pr.GetNumber())
+		pkg.ArchivedPRNumber = pr.GetNumber()
+		return true, nil
+	}
+	if pkg.ArchivedPRNumber != 0 {
+		logger.Info("keep the package because the pull request removing the package was closed", "pr_number", pkg.ArchivedPRNumber)
+		return false, nil
+	}
+	if ok, err := c.checkBranch(ctx, branch); err != nil {
+		return false, fmt.Errorf("check a branch: %w", err)
+	} else if ok {
+		// Creating the pull request may have failed. If branch_cleanup is enabled, the stale branch is deleted and the pull request is created again.
+		logger.Info("the branch to remove the package already exists but the pull request isn't found", "branch", branch)
+		return false, nil
+	}
+
+	pkgDir := filepath.Join("pkgs", filepath.FromSlash(pkg.Name))
+	files := []string{
+		filepath.Join(pkgDir, "pkg.yaml"),
+		filepath.Join(pkgDir, "registry.yaml"),
+	}
+	defer func() {
+		if err := c.exec(ctx, "git", "checkout", "--", "."); err != nil && e == nil {
+			e = err
+		}
+	}()
+	for _, file := range files {
+		if err := c.fs.Remove(file); err != nil {
+			return false, fmt.Errorf("remove a file: %w", err)
+		}
+	}
+	if err := genrg.GenerateRegistry(ctx); err != nil {
+		return false, fmt.Errorf("update registry.yaml: %w", err)
+	}
+
+	paramTemplates := &ParamTemplates{
+		ServerURL:   c.param.URLs.ServerURL,
+		PackageName: pkg.Name,
+		RepoOwner:   pkgInfo.RepoOwner,
+		RepoName:    pkgInfo.RepoName,
+	}
+	prTitle, err := renderTemplate(cfg.compiledTemplates.ArchivedPRTitle, paramTemplates)
+	if err != nil {
+		return false, fmt.Errorf("render a template archived_pr_title: %w", err)
+	}
+	prBody, err := renderTemplate(cfg.compiledTemplates.ArchivedPRBody, paramTemplates)
+	if err != nil {
+		return false, fmt.Errorf("render a template archived_pr_body: %w", err)
+	}
+
+	prCfg := cfg.PullRequest.ForPackage(pkg.Name)
+	if _, err := c.commit(ctx, &ParamCommit{
+		Base:      prCfg.Base,
+		Branch:    branch,
+		Message:   prTitle,
+		Files:     []string{"registry.yaml"},
+		Deletions: files,
+	}); err != nil {
+		if errors.Is(err, errNothingToCommit) {
+			logger.Info("the package isn't found in the base branch, so a pull request isn't created")
+			return false, nil
+		}
+		return false, fmt.Errorf("create a branch: %w", err)
+	}
+	pr, err := c.createPR(ctx, logger, &ParamCreatePR{
+		Title:  prTitle,
+		Branch: branch,
+		Body:   prBody,
+		Config: prCfg,
+	})
+	if err != nil {
+		return false, fmt.Errorf("create a pull request: %w", err)
+	}
+	state.openPRs = append(state.openPRs, pr)
+	pkg.ArchivedPRNumber = pr.GetNumber()
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"github.com/google/go-github/v89/github"
+)
+
+func TestController_handleArchived(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name     string
+		pkg      *Package
+		archived bool
+		branch   bool
+		openPRs  []*github.PullRequest
+		exp      bool
+		prNumber int
+	}{
+		{
+			name: "not archived",
+			pkg:  &Package{Name: "foo/bar"},
+		},
+		{
+			name: "unarchived",
+			pkg:  &Package{Name: "foo/bar", ArchivedPRNumber: 10},
+		},
+		{
+			name:     "the pull request is open",
+			pkg:      &Package{Name: "foo/bar"},
+			archived: true,
+			openPRs: []*github.PullRequest{
+				{Number: new(10), Head: &github.PullRequestBranch{Ref: new("aqua-registry-updater-archived-foo/bar")}},
+			},
+			exp:      true,
+			prNumber: 10,
+		},
+		{
+			name:     "the pull request was closed",
+			pkg:      &Package{Name: "foo/bar", ArchivedPRNumber: 10},
+			archived: true,
+			openPRs: []*github.PullRequest{
+				{Number: new(11), Head: &github.PullRequestBranch{Ref: new("aqua-registry-updater-foo/bar-v1.0.0")}},
+			},
+			prNumber: 10,
+		},
+		{
+			name:     "the branch exists but the pull request isn't found",
+			pkg:      &Package{Name: "foo/bar"},
+			archived: true,
+			branch:   true,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			git := newFakeGit()
+			if d.branch {
+				git.refs["heads/aqua-registry-updater-archived-foo/bar"] = "root"
+			}
+			ctrl := &Controller{
+				git:   git,
+				param: &ParamNew{RepoOwner: "aquaproj", RepoName: "aqua-registry", URLs: &GitHubURLs{ServerURL: defaultServerURL}},
+			}
+			archived, err := ctrl.handleArchived(context.Background(), logger, d.pkg, &Config{}, &runState{openPRs: d.openPRs}, &upstreamRepository{
+				pkgInfo: &registry.PackageInfo{RepoOwner: "foo", RepoName: "bar"},
+				repo:    &github.Repository{Archived: new(d.archived)},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if archived != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, archived)
+			}
+			if d.pkg.ArchivedPRNumber != d.prNumber {
+				t.Fatalf("wanted the pull request number %d, got %d", d.prNumber, d.pkg.ArchivedPRNumber)
+			}
+		})
+	}
+}
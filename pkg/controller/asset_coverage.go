@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aquaproj/aqua/v2/pkg/config"
+	"github.com/aquaproj/aqua/v2/pkg/config/aqua"
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"github.com/aquaproj/aqua/v2/pkg/runtime"
+)
+
+// missingAsset is an asset of a supported platform which isn't found in a release.
+type missingAsset struct {
+	Env   string
+	Asset string
+}
+
+// checkAssetCoverage returns assets of supported platforms which aren't found in the release of the new version.
+// Only github_release packages are checked.
+func (c *Controller) checkAssetCoverage(ctx context.Context, logger *slog.Logger, pkgInfo *registry.PackageInfo, version string) ([]*missingAsset, error) {
+	if pkgInfo.Type != registry.PkgInfoTypeGitHubRelease || pkgInfo.RepoOwner == "" || pkgInfo.RepoName == "" {
+		return nil, nil
+	}
+	release, _, err := c.repo.GetReleaseByTag(ctx, pkgInfo.RepoOwner, pkgInfo.RepoName, version)
+	if err != nil {
+		return nil, fmt.Errorf("get a release: %w", err)
+	}
+	assets := make([]string, 0, len(release.Assets))
+	for _, a := range release.Assets {
+		assets = append(assets, a.GetName())
+	}
+	return findMissingAssets(logger, pkgInfo, version, assets)
+}
+
+// findMissingAssets renders the asset template for every supported platform and returns assets which aren't in assets.
+// version_overrides, overrides and default values of vars are applied as aqua does.
+func findMissingAssets(logger *slog.Logger, pkgInfo *registry.PackageInfo, version string, assets []string) ([]*missingAsset, error) {
+	pkgInfo, err := pkgInfo.Copy().SetVersion(logger, version)
+	if err != nil {
+		return nil, fmt.Errorf("apply version_overrides: %w", err)
+	}
+	rts, err := runtime.GetRuntimesFromEnvs(pkgInfo.SupportedEnvs)
+	if err != nil {
+		return nil, fmt.Errorf("parse supported_envs: %w", err)
+	}
+	assetM := make(map[string]struct{}, len(assets))
+	for _, a := range assets {
+		assetM[a] = struct{}{}
+	}
+	missing := []*missingAsset{}
+	for _, rt := range rts {
+		p := pkgInfo.Copy()
+		p.OverrideByRuntime(rt)
+		if p.Type != registry.PkgInfoTypeGitHubRelease || p.NoAsset {
+			continue
+		}
+		pkg := &config.Package{
+			PackageInfo: p,
+			Package:     &aqua.Package{Version: version},
+		}
+		if err := pkg.ApplyVars(); err != nil {
+			return nil, fmt.Errorf("apply vars: %w", err)
+		}
+		assetName, err := pkg.RenderAsset(rt)
+		if err != nil {
+			return nil, fmt.Errorf("render an asset of %s: %w", rt.Env(), err)
+		}
+		if _, ok := assetM[assetName]; ok {
+			continue
+		}
+		missing = append(missing, &missingAsset{
+			Env:   rt.Env(),
+			Asset: assetName,
+		})
+	}
+	return missing, nil
+}
+
+func formatMissingAssets(missing []*missingAsset) string {
+	arr := make([]string, len(missing))
+	for i, m := range missing {
+		arr[i] = fmt.Sprintf("%s (`%s`)", m.Env, m.Asset)
+	}
+	return strings.Join(arr, ", ")
+}
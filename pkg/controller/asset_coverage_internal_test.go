@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_findMissingAssets(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name    string
+		pkgInfo string
+		version string
+		assets  []string
+		exp     []string
+	}{
+		{
+			name: "all assets exist",
+			pkgInfo: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar_{{trimV .Version}}_{{.OS}}_{{.Arch}}.{{.Format}}
+format: tar.gz
+replacements:
+  darwin: macOS
+overrides:
+  - goos: windows
+    format: zip
+supported_envs:
+  - darwin
+  - windows
+`,
+			version: "v1.0.0",
+			assets: []string{
+				"bar_1.0.0_macOS_amd64.tar.gz",
+				"bar_1.0.0_macOS_arm64.tar.gz",
+				"bar_1.0.0_windows_amd64.zip",
+				"bar_1.0.0_windows_arm64.zip",
+			},
+			exp: []string{},
+		},
+		{
+			name: "platforms are dropped",
+			pkgInfo: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar_{{.OS}}_{{.Arch}}
+supported_envs:
+  - linux
+  - windows/amd64
+`,
+			version: "v1.0.0",
+			assets: []string{
+				"bar_linux_amd64",
+			},
+			exp: []string{
+				"linux/arm64: bar_linux_arm64",
+				"windows/amd64: bar_windows_amd64.exe",
+			},
+		},
+		{
+			name: "version_overrides",
+			pkgInfo: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar_{{.OS}}_{{.Arch}}.tar.gz
+supported_envs:
+  - linux/amd64
+version_constraint: "false"
+version_overrides:
+  - version_constraint: semver("< 2.0.0")
+    asset: bar_{{.OS}}.tar.gz
+  - version_constraint: "true"
+`,
+			version: "v1.0.0",
+			assets: []string{
+				"bar_linux.tar.gz",
+			},
+			exp: []string{},
+		},
+		{
+			name: "vars",
+			pkgInfo: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar_{{.Vars.flavor}}_{{.OS}}_{{.Arch}}.tar.gz
+vars:
+  - name: flavor
+    default: full
+supported_envs:
+  - linux
+`,
+			version: "v1.0.0",
+			assets: []string{
+				"bar_full_linux_amd64.tar.gz",
+			},
+			exp: []string{
+				"linux/arm64: bar_full_linux_arm64.tar.gz",
+			},
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			pkgInfo := &registry.PackageInfo{}
+			if err := yaml.Unmarshal([]byte(d.pkgInfo), pkgInfo); err != nil {
+				t.Fatal(err)
+			}
+			missing, err := findMissingAssets(logger, pkgInfo, d.version, d.assets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(missing))
+			for i, m := range missing {
+				got[i] = m.Env + ": " + m.Asset
+			}
+			if !slices.Equal(got, d.exp) {
+				t.Fatalf("wanted %v, got %v", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+// cleanupBranches deletes branches of aqua-registry-updater which have no open pull request.
+// Such branches are left when pull requests are closed or creating pull requests fails.
+func (c *Controller) cleanupBranches(ctx context.Context, logger *slog.Logger, cfg *BranchCleanupConfig, state *runState) error {
+	refs, _, err := c.git.ListMatchingRefs(ctx, c.param.RepoOwner, c.param.RepoName, "heads/"+branchPrefix)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	openBranches := make(map[string]struct{}, len(state.openPRs))
+	for _, pr := range state.openPRs {
+		openBranches[pr.GetHead().GetRef()] = struct{}{}
+	}
+	now := time.Now()
+	for _, ref := range refs {
+		branch := strings.TrimPrefix(ref.GetRef(), "refs/heads/")
+		if _, ok := openBranches[branch]; ok {
+			continue
+		}
+		logger := logger.With("branch", branch)
+		commit, _, err := c.git.GetCommit(ctx, c.param.RepoOwner, c.param.RepoName, ref.GetObject().GetSHA())
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("get the latest commit of a branch")
+			continue
+		}
+		if age := now.Sub(commit.GetCommitter().GetDate().Time); age < cfg.MinAge {
+			continue
+		}
+		if cfg.DryRun {
+			logger.Info("[dry run] deleting a stale branch")
+			continue
+		}
+		logger.Info("deleting a stale branch")
+		if err := c.deleteBranch(ctx, branch); err != nil {
+			slogerr.WithError(logger, err).Error("delete a stale branch")
+		}
+	}
+	return nil
+}
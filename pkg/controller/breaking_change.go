@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BreakingChangeConfig is the configuration to detect breaking changes from release notes.
+// If release notes match any pattern, auto-merge is disabled.
+type BreakingChangeConfig struct {
+	// Patterns are regular expressions matched against release notes.
+	Patterns         []string
+	compiledPatterns []*regexp.Regexp
+}
+
+// defaultBreakingChangePatterns match headings, bold labels, and footers of Conventional Commits about breaking changes.
+// Headings may start with an emoji such as ":warning:".
+var defaultBreakingChangePatterns = []string{
+	`(?im)^#+\s*(?:(?::\w+:|[^\w\s]+)\s*)?breaking[ _-]?changes?\b`,
+	`(?im)^\s*(?:[-*]\s+)?\*\*breaking[ _-]?changes?\b`,
+	`(?m)^\s*(?:[-*]\s+)?BREAKING[ _-]CHANGES?:`,
+	`(?i)backwards?[ -]incompatib`,
+}
+
+// breakingChangeNegation matches the text before a match if it's negated such as "There are no backward-incompatible changes".
+var breakingChangeNegation = regexp.MustCompile(`(?i)\b(?:no|non|not|without)[\s-]+(?:\S+[\s-]+)?$`)
+
+func (b *BreakingChangeConfig) compile() error {
+	b.compiledPatterns = make([]*regexp.Regexp, len(b.Patterns))
+	for i, pattern := range b.Patterns {
+		p, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("compile a regular expression %s: %w", pattern, err)
+		}
+		b.compiledPatterns[i] = p
+	}
+	return nil
+}
+
+// findBreakingChanges returns tags of releases whose release notes match any pattern.
+// Matches negated by words such as "no" and "without" in the same line are ignored.
+func (b *BreakingChangeConfig) findBreakingChanges(releases []*Release) []string {
+	tags := []string{}
+	for _, release := range releases {
+		if b.hasBreakingChange(release.Body) {
+			tags = append(tags, release.TagName)
+		}
+	}
+	return tags
+}
+
+func (b *BreakingChangeConfig) hasBreakingChange(body string) bool {
+	for _, p := range b.compiledPatterns {
+		for _, loc := range p.FindAllStringIndex(body, -1) {
+			lineStart := strings.LastIndexByte(body[:loc[0]], '\n') + 1
+			if !breakingChangeNegation.MatchString(body[lineStart:loc[0]]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatWarnings formats warnings as GitHub alerts to be prepended to pull request bodies.
+func formatWarnings(warnings []string) string {
+	b := &strings.Builder{}
+	for _, warning := range warnings {
+		fmt.Fprintf(b, "> [!WARNING]\n> %s\n\n", warning)
+	}
+	return b.String()
+}
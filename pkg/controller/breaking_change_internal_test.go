@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBreakingChangeConfig_findBreakingChanges(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "breaking change",
+			body: "## Breaking Changes\n\n- Remove the option --foo",
+			want: true,
+		},
+		{
+			name: "conventional commits",
+			body: "* feat!: rename the command\n\nBREAKING CHANGE: the command was renamed",
+			want: true,
+		},
+		{
+			name: "backward incompatible",
+			body: "This release is backward incompatible.",
+			want: true,
+		},
+		{
+			name: "heading with emoji",
+			body: "## :warning: Breaking Changes\n\n- Remove the option --foo",
+			want: true,
+		},
+		{
+			name: "bold label",
+			body: "- **Breaking change**: the config file was renamed",
+			want: true,
+		},
+		{
+			name: "no breaking changes",
+			body: "## Bug Fixes\n\n- Fix a typo\n\nThere are no breaking changes.",
+		},
+		{
+			name: "no breaking changes heading",
+			body: "## No breaking changes\n\n- Fix a typo",
+		},
+		{
+			name: "no backward incompatible changes",
+			body: "There are no backward-incompatible changes",
+		},
+		{
+			name: "without backward incompatible changes",
+			body: "This release adds features without backwards incompatible changes.",
+		},
+		{
+			name: "negation in another line",
+			body: "- No new features\n\nThis release is backward incompatible.",
+			want: true,
+		},
+		{
+			name: "no breaking changes in a list",
+			body: "- No breaking changes",
+		},
+		{
+			name: "non-breaking change",
+			body: "## Features\n\n- Add the option --bar. This is a non-breaking change.",
+		},
+		{
+			name: "no breaking change",
+			body: "## Bug Fixes\n\n- Fix a typo",
+		},
+	}
+	cfg := &BreakingChangeConfig{
+		Patterns: defaultBreakingChangePatterns,
+	}
+	if err := cfg.compile(); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			tags := cfg.findBreakingChanges([]*Release{
+				{
+					TagName: "v2.0.0",
+					Body:    d.body,
+				},
+			})
+			if got := slices.Contains(tags, "v2.0.0"); got != d.want {
+				t.Fatalf("wanted %v, got %v", d.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/spf13/afero"
+)
+
+// errNothingToCommit is returned when a new branch would have no change from the base branch.
+// The branch isn't created, so callers shouldn't create a pull request.
+var errNothingToCommit = errors.New("nothing to commit")
+
+type ParamCommit struct {
+	// Base is the branch from which a new branch is created.
+	Base    string
+	Branch  string
+	Message string
+	// Files are paths of files to add or update.
+	Files []string
+	// Deletions are paths of files to delete.
+	Deletions []string
+}
+
+// commit pushes a commit to a branch through GitHub Git Data API and returns the commit SHA.
+// If the branch doesn't exist, it's created from the base branch.
+// If the new branch would have no change from the base branch, it isn't created and errNothingToCommit is returned.
+// Commits created through the API are signed by GitHub.
+func (c *Controller) commit(ctx context.Context, param *ParamCommit) (string, error) {
+	branchRef := "heads/" + param.Branch
+	parentSHA, branchExists, err := c.getRefSHA(ctx, branchRef)
+	if err != nil {
+		return "", fmt.Errorf("get a branch: %w", err)
+	}
+	if !branchExists {
+		sha, baseExists, err := c.getRefSHA(ctx, "heads/"+param.Base)
+		if err != nil {
+			return "", fmt.Errorf("get a base branch: %w", err)
+		}
+		if !baseExists {
+			return "", errors.New("the base branch isn't found")
+		}
+		parentSHA = sha
+	}
+
+	parent, _, err := c.git.GetCommit(ctx, c.param.RepoOwner, c.param.RepoName, parentSHA)
+	if err != nil {
+		return "", fmt.Errorf("get a parent commit: %w", err)
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(param.Files)+len(param.Deletions))
+	for _, file := range param.Files {
+		content, err := afero.ReadFile(c.fs, file)
+		if err != nil {
+			return "", fmt.Errorf("read a file: %w", err)
+		}
+		blob, _, err := c.git.CreateBlob(ctx, c.param.RepoOwner, c.param.RepoName, github.Blob{
+			Content:  new(base64.StdEncoding.EncodeToString(content)),
+			Encoding: new("base64"),
+		})
+		if err != nil {
+			return "", fmt.Errorf("create a blob: %w", err)
+		}
+		entries = append(entries, &github.TreeEntry{
+			Path: new(filepath.ToSlash(file)),
+			Mode: new("100644"),
+			Type: new("blob"),
+			SHA:  blob.SHA,
+		})
+	}
+	for _, file := range param.Deletions {
+		// An entry without both SHA and Content deletes the file.
+		// GitHub requires mode and type even for deletions.
+		entries = append(entries, &github.TreeEntry{
+			Path: new(filepath.ToSlash(file)),
+			Mode: new("100644"),
+			Type: new("blob"),
+		})
+	}
+
+	tree, _, err := c.git.CreateTree(ctx, c.param.RepoOwner, c.param.RepoName, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return "", fmt.Errorf("create a tree: %w", err)
+	}
+	if tree.GetSHA() == parent.GetTree().GetSHA() {
+		if !branchExists {
+			return "", errNothingToCommit
+		}
+		// Nothing to commit
+		return parentSHA, nil
+	}
+
+	commit, _, err := c.git.CreateCommit(ctx, c.param.RepoOwner, c.param.RepoName, github.Commit{
+		Message: new(param.Message),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: new(parentSHA)}},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("create a commit: %w", err)
+	}
+
+	if branchExists {
+		if _, _, err := c.git.UpdateRef(ctx, c.param.RepoOwner, c.param.RepoName, branchRef, github.UpdateRef{
+			SHA: commit.GetSHA(),
+		}); err != nil {
+			return "", fmt.Errorf("update a branch: %w", err)
+		}
+		return commit.GetSHA(), nil
+	}
+	if _, _, err := c.git.CreateRef(ctx, c.param.RepoOwner, c.param.RepoName, github.CreateRef{
+		Ref: "refs/" + branchRef,
+		SHA: commit.GetSHA(),
+	}); err != nil {
+		return "", fmt.Errorf("create a branch: %w", err)
+	}
+	return commit.GetSHA(), nil
+}
+
+// getRefSHA returns the SHA of a reference.
+// If the reference isn't found, it returns false without an error.
+func (c *Controller) getRefSHA(ctx context.Context, ref string) (string, bool, error) {
+	r, resp, err := c.git.GetRef(ctx, c.param.RepoOwner, c.param.RepoName, ref)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("get a reference: %w", err)
+	}
+	return r.GetObject().GetSHA(), true, nil
+}
@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/spf13/afero"
+)
+
+// fakeGit is an in-memory implementation of GitService.
+type fakeGit struct {
+	refs    map[string]string
+	commits map[string]*github.Commit
+	trees   map[string]map[string]string
+	blobs   map[string]string
+	// entries are tree entries passed to CreateTree.
+	entries []*github.TreeEntry
+}
+
+func newFakeGit() *fakeGit {
+	g := &fakeGit{
+		refs:    map[string]string{},
+		commits: map[string]*github.Commit{},
+		trees:   map[string]map[string]string{},
+		blobs:   map[string]string{},
+	}
+	tree := g.addTree(map[string]string{
+		"registry.yaml":       "packages: []\n",
+		"pkgs/foo/bar/a.yaml": "a\n",
+	})
+	g.commits["root"] = &github.Commit{SHA: new("root"), Tree: &github.Tree{SHA: new(tree)}}
+	g.refs["heads/main"] = "root"
+	return g
+}
+
+func hash(s string) string {
+	b := sha1.Sum([]byte(s)) //nolint:gosec
+	return hex.EncodeToString(b[:])
+}
+
+func (g *fakeGit) addTree(files map[string]string) string {
+	keys := slices.Sorted(maps.Keys(files))
+	b := &strings.Builder{}
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s\x00%s\x00", k, files[k])
+	}
+	sha := hash(b.String())
+	g.trees[sha] = files
+	return sha
+}
+
+func notFound() (*github.Response, error) {
+	resp := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	return resp, &github.ErrorResponse{Response: resp.Response}
+}
+
+func (g *fakeGit) DeleteRef(_ context.Context, _, _, ref string) (*github.Response, error) {
+	delete(g.refs, ref)
+	return &github.Response{}, nil
+}
+
+func (g *fakeGit) ListMatchingRefs(_ context.Context, _, _, _ string) ([]*github.Reference, *github.Response, error) {
+	return nil, &github.Response{}, nil
+}
+
+func (g *fakeGit) GetCommit(_ context.Context, _, _, sha string) (*github.Commit, *github.Response, error) {
+	commit, ok := g.commits[sha]
+	if !ok {
+		resp, err := notFound()
+		return nil, resp, err
+	}
+	return commit, &github.Response{}, nil
+}
+
+func (g *fakeGit) GetRef(_ context.Context, _, _, ref string) (*github.Reference, *github.Response, error) {
+	sha, ok := g.refs[ref]
+	if !ok {
+		resp, err := notFound()
+		return nil, resp, err
+	}
+	return &github.Reference{Ref: new("refs/" + ref), Object: &github.GitObject{SHA: new(sha)}}, &github.Response{}, nil
+}
+
+func (g *fakeGit) CreateRef(_ context.Context, _, _ string, ref github.CreateRef) (*github.Reference, *github.Response, error) {
+	g.refs[strings.TrimPrefix(ref.Ref, "refs/")] = ref.SHA
+	return &github.Reference{}, &github.Response{}, nil
+}
+
+func (g *fakeGit) UpdateRef(_ context.Context, _, _, ref string, updateRef github.UpdateRef) (*github.Reference, *github.Response, error) {
+	g.refs[ref] = updateRef.SHA
+	return &github.Reference{}, &github.Response{}, nil
+}
+
+func (g *fakeGit) CreateBlob(_ context.Context, _, _ string, blob github.Blob) (*github.Blob, *github.Response, error) {
+	b, err := base64.StdEncoding.DecodeString(blob.GetContent())
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+	sha := hash(string(b))
+	g.blobs[sha] = string(b)
+	return &github.Blob{SHA: new(sha)}, &github.Response{}, nil
+}
+
+func (g *fakeGit) CreateTree(_ context.Context, _, _, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error) {
+	g.entries = append(g.entries, entries...)
+	files := maps.Clone(g.trees[baseTree])
+	for _, entry := range entries {
+		if entry.SHA == nil {
+			delete(files, entry.GetPath())
+			continue
+		}
+		files[entry.GetPath()] = g.blobs[entry.GetSHA()]
+	}
+	return &github.Tree{SHA: new(g.addTree(files))}, &github.Response{}, nil
+}
+
+func (g *fakeGit) CreateCommit(_ context.Context, _, _ string, commit github.Commit, _ *github.CreateCommitOptions) (*github.Commit, *github.Response, error) {
+	sha := hash(commit.GetMessage() + commit.GetTree().GetSHA() + commit.Parents[0].GetSHA())
+	g.commits[sha] = &github.Commit{SHA: new(sha), Tree: commit.Tree, Parents: commit.Parents}
+	return g.commits[sha], &github.Response{}, nil
+}
+
+func (g *fakeGit) files(t *testing.T, branch string) map[string]string {
+	t.Helper()
+	commit, ok := g.commits[g.refs["heads/"+branch]]
+	if !ok {
+		t.Fatalf("branch %s isn't found", branch)
+	}
+	return g.trees[commit.GetTree().GetSHA()]
+}
+
+func TestController_commit(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "registry.yaml", []byte("packages: [foo]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "pkgs/foo/bar/b.yaml", []byte("b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git := newFakeGit()
+	ctrl := &Controller{
+		fs:    fs,
+		git:   git,
+		param: &ParamNew{RepoOwner: "aquaproj", RepoName: "aqua-registry"},
+	}
+
+	// Create a new branch from the base branch
+	sha, err := ctrl.commit(t.Context(), &ParamCommit{
+		Base:      "main",
+		Branch:    "aqua-registry-updater-foo/bar-v1.0.0",
+		Message:   "update foo/bar",
+		Files:     []string{"registry.yaml", "pkgs/foo/bar/b.yaml"},
+		Deletions: []string{"pkgs/foo/bar/a.yaml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent := git.commits[sha].Parents[0].GetSHA(); parent != "root" {
+		t.Fatalf("the parent commit must be root, got %s", parent)
+	}
+	want := map[string]string{
+		"registry.yaml":       "packages: [foo]\n",
+		"pkgs/foo/bar/b.yaml": "b\n",
+	}
+	if got := git.files(t, "aqua-registry-updater-foo/bar-v1.0.0"); !maps.Equal(got, want) {
+		t.Fatalf("wanted %v, got %v", want, got)
+	}
+	if git.refs["heads/main"] != "root" {
+		t.Fatal("the base branch must not be changed")
+	}
+	// The deletion is sent as an entry whose sha is null
+	idx := slices.IndexFunc(git.entries, func(entry *github.TreeEntry) bool {
+		return entry.GetPath() == "pkgs/foo/bar/a.yaml"
+	})
+	if idx == -1 {
+		t.Fatal("a tree entry to delete a file isn't found")
+	}
+	b, err := json.Marshal(git.entries[idx])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantBody := `{"sha":null,"path":"pkgs/foo/bar/a.yaml","mode":"100644","type":"blob"}`; string(b) != wantBody {
+		t.Fatalf("wanted %s, got %s", wantBody, string(b))
+	}
+
+	// Nothing to commit
+	sha2, err := ctrl.commit(t.Context(), &ParamCommit{
+		Base:    "main",
+		Branch:  "aqua-registry-updater-foo/bar-v1.0.0",
+		Message: "update foo/bar",
+		Files:   []string{"registry.yaml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha2 != sha {
+		t.Fatalf("a commit must not be created if nothing is changed: wanted %s, got %s", sha, sha2)
+	}
+
+	// Push a commit to the existing branch
+	if err := afero.WriteFile(fs, "registry.yaml", []byte("packages: [foo, bar]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sha3, err := ctrl.commit(t.Context(), &ParamCommit{
+		Base:    "main",
+		Branch:  "aqua-registry-updater-foo/bar-v1.0.0",
+		Message: "update registry.yaml",
+		Files:   []string{"registry.yaml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent := git.commits[sha3].Parents[0].GetSHA(); parent != sha {
+		t.Fatalf("the parent commit must be %s, got %s", sha, parent)
+	}
+	if got := git.files(t, "aqua-registry-updater-foo/bar-v1.0.0")["registry.yaml"]; got != "packages: [foo, bar]\n" {
+		t.Fatalf("registry.yaml isn't updated: %s", got)
+	}
+
+	// A new branch isn't created if nothing is changed from the base branch
+	if err := afero.WriteFile(fs, "registry.yaml", []byte("packages: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commits := len(git.commits)
+	if _, err := ctrl.commit(t.Context(), &ParamCommit{
+		Base:    "main",
+		Branch:  "aqua-registry-updater-foo/bar-v3.0.0",
+		Message: "update foo/bar",
+		Files:   []string{"registry.yaml"},
+	}); !errors.Is(err, errNothingToCommit) {
+		t.Fatalf("errNothingToCommit must be returned, got %v", err)
+	}
+	if len(git.commits) != commits {
+		t.Fatal("a commit must not be created")
+	}
+	if _, ok := git.refs["heads/aqua-registry-updater-foo/bar-v3.0.0"]; ok {
+		t.Fatal("a branch must not be created")
+	}
+
+	// The base branch isn't found
+	if _, err := ctrl.commit(t.Context(), &ParamCommit{
+		Base:    "develop",
+		Branch:  "aqua-registry-updater-foo/bar-v2.0.0",
+		Message: "update foo/bar",
+		Files:   []string{"registry.yaml"},
+	}); err == nil {
+		t.Fatal("error must be returned")
+	}
+}
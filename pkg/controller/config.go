@@ -3,6 +3,9 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,26 +23,138 @@ func (c *Controller) readConfig(path string, cfg *Config) error {
 }
 
 type Config struct {
+	// Limit is the maximum number of packages handled in a run.
+	// The run also stops before GitHub API rate limits drop below RateLimit's reserves.
 	Limit             int
 	ContainerRegistry *ContainerRegistry `yaml:"container_registry"`
 	IgnorePackages    []string           `yaml:"ignore_packages"`
-	Templates         *Templates
-	compiledTemplates *CompiledTemplates
-	Scaffold          *ScaffoldConfig `yaml:"scaffold"`
+	// MaxOpenPRs is the maximum number of open pull requests created by aqua-registry-updater.
+	// If the number reaches it, no more pull request is created. 0 means no limit.
+	MaxOpenPRs int `yaml:"max_open_prs"`
+	// MaxAutoMergePerDay is the maximum number of pull requests whose auto-merge is enabled in 24 hours.
+	// If the number reaches it, auto-merge isn't enabled. 0 means no limit.
+	MaxAutoMergePerDay int `yaml:"max_automerge_per_day"`
+	Templates          *Templates
+	compiledTemplates  *CompiledTemplates
+	Scaffold           *ScaffoldConfig       `yaml:"scaffold"`
+	Archived           *ArchivedConfig       `yaml:"archived"`
+	RepositoryGone     *RepositoryGoneConfig `yaml:"repository_gone"`
+	Transfer           *TransferConfig       `yaml:"transfer"`
+	BranchCleanup      *BranchCleanupConfig  `yaml:"branch_cleanup"`
+	PullRequest        *PullRequestConfig    `yaml:"pull_request"`
+	AutoMerge          *AutoMergeConfig      `yaml:"auto_merge"`
+	BreakingChange     *BreakingChangeConfig `yaml:"breaking_change"`
+	Dashboard          *DashboardConfig      `yaml:"dashboard"`
+	RateLimit          *RateLimitConfig      `yaml:"rate_limit"`
 }
 
 type ScaffoldConfig struct {
 	Enabled bool
+	// Interval is the minimum interval to re-scaffold each package.
+	// Re-scaffolding is skipped until the interval passes, so that version updates aren't crowded out.
+	Interval time.Duration
+	// KeepFields are fields of registry.yaml kept when the package is re-scaffolded.
+	// Fields annotated with the comment "# aqua-registry-updater:keep" are also kept.
+	KeepFields []string `yaml:"keep_fields"`
+	// Types are types of packages which are re-scaffolded.
+	// aqua gr generates only github_release and cargo packages, so other types such as http and go_install aren't supported.
+	Types []string
 }
 
+// scaffoldTypes are types of packages which can be re-scaffolded.
+var scaffoldTypes = []string{"github_release", "cargo"} //nolint:gochecknoglobals
+
 func (s *ScaffoldConfig) IsEnabled() bool {
 	return s != nil && s.Enabled
 }
 
+// TransferConfig is the configuration of pull requests for transferred repositories.
+type TransferConfig struct {
+	// AddAlias adds the old package name to aliases of the new package so that aqua.yaml using the old name keeps working.
+	AddAlias bool `yaml:"add_alias"`
+}
+
+// BranchCleanupConfig is the configuration to delete branches of aqua-registry-updater which have no open pull request.
+type BranchCleanupConfig struct {
+	Enabled bool
+	DryRun  bool `yaml:"dry_run"`
+	// MinAge is the minimum age of the latest commit of branches to delete.
+	// Branches created recently are kept because their pull requests may be being created.
+	MinAge time.Duration `yaml:"min_age"`
+}
+
+func (b *BranchCleanupConfig) IsEnabled() bool {
+	return b != nil && b.Enabled
+}
+
+type AutoMergeConfig struct {
+	// MergeMethod is one of "squash", "merge", and "rebase".
+	MergeMethod string `yaml:"merge_method"`
+}
+
 func (c *Config) SetDefault(repo string) error { //nolint:cyclop,funlen
 	if c.Limit == 0 {
 		c.Limit = 50
 	}
+	if c.Scaffold.IsEnabled() {
+		if c.Scaffold.Interval == 0 {
+			c.Scaffold.Interval = 90 * 24 * time.Hour
+		}
+		if c.Scaffold.Types == nil {
+			c.Scaffold.Types = []string{"github_release", "cargo"}
+		}
+		for _, typ := range c.Scaffold.Types {
+			if !slices.Contains(scaffoldTypes, typ) {
+				return fmt.Errorf("scaffold.types must be one of %s: %s", strings.Join(scaffoldTypes, ", "), typ)
+			}
+		}
+	}
+	if c.BranchCleanup.IsEnabled() && c.BranchCleanup.MinAge == 0 {
+		c.BranchCleanup.MinAge = 7 * 24 * time.Hour
+	}
+	if c.Transfer == nil {
+		c.Transfer = &TransferConfig{}
+	}
+	if c.PullRequest == nil {
+		c.PullRequest = &PullRequestConfig{}
+	}
+	if c.PullRequest.Base == "" {
+		c.PullRequest.Base = "main"
+	}
+	if c.AutoMerge == nil {
+		c.AutoMerge = &AutoMergeConfig{}
+	}
+	switch c.AutoMerge.MergeMethod {
+	case "":
+		c.AutoMerge.MergeMethod = "squash"
+	case "squash", "merge", "rebase":
+	default:
+		return errors.New("auto_merge.merge_method must be one of squash, merge, and rebase")
+	}
+	if c.BreakingChange == nil {
+		c.BreakingChange = &BreakingChangeConfig{}
+	}
+	if c.BreakingChange.Patterns == nil {
+		c.BreakingChange.Patterns = defaultBreakingChangePatterns
+	}
+	if err := c.BreakingChange.compile(); err != nil {
+		return fmt.Errorf("compile breaking_change.patterns: %w", err)
+	}
+	if c.Dashboard.IsEnabled() && c.Dashboard.Title == "" {
+		c.Dashboard.Title = "Dependency Dashboard (aqua-registry-updater)"
+	}
+	if c.RateLimit == nil {
+		c.RateLimit = &RateLimitConfig{}
+	}
+	if c.RateLimit.CoreReserve == nil {
+		c.RateLimit.CoreReserve = new(defaultCoreReserve)
+	}
+	if c.RateLimit.GraphQLReserve == nil {
+		c.RateLimit.GraphQLReserve = new(defaultGraphQLReserve)
+	}
+	if c.RateLimit.MaxRetryAfter == 0 {
+		c.RateLimit.MaxRetryAfter = defaultMaxRetryAfter
+	}
 	if c.ContainerRegistry == nil {
 		return errors.New("container_registry is required")
 	}
@@ -64,15 +179,19 @@ func (c *Config) SetDefault(repo string) error { //nolint:cyclop,funlen
 	if c.Templates.PRBody == "" {
 		c.Templates.PRBody = `[{{.NewVersion}}]({{.ReleaseURL}}) [compare]({{.CompareURL}})
 
-This pull request was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
+{{.ReleaseNotes}}This pull request was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
 	}
 
 	if c.Templates.TransferPRTitle == "" {
 		c.Templates.TransferPRTitle = "fix({{.PackageName}}): transfer the repository to {{.NewRepoOwner}}/{{.NewRepoName}}"
 	}
 	if c.Templates.TransferPRBody == "" {
-		c.Templates.TransferPRBody = `The GitHub Repository of the package "{{.PackageName}}" was transferred from [{{.RepoOwner}}/{{.RepoName}}](https://github.com/{{.RepoOwner}}/{{.RepoName}}) to [{{.NewRepoOwner}}/{{.NewRepoName}}](https://github.com/{{.NewRepoOwner}}/{{.NewRepoName}})
-
+		c.Templates.TransferPRBody = `The GitHub Repository of the package "{{.PackageName}}" was transferred from [{{.RepoOwner}}/{{.RepoName}}]({{.ServerURL}}/{{.RepoOwner}}/{{.RepoName}}) to [{{.NewRepoOwner}}/{{.NewRepoName}}]({{.ServerURL}}/{{.NewRepoOwner}}/{{.NewRepoName}})
+{{if .Duplicated}}
+The package "{{.NewPackageName}}" already exists, so this pull request removes the duplicated package "{{.PackageName}}".
+{{end}}{{if .Alias}}
+The old package name "{{.Alias}}" was added to aliases of the package "{{.NewPackageName}}" so that aqua.yaml using the old name keeps working.
+{{end}}
 This pull request was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
 	}
 
@@ -80,13 +199,40 @@ This pull request was created by [aqua-registry-updater](https://github.com/aqua
 		c.Templates.ScaffoldPRTitle = "Re-scaffold {{.PackageName}}"
 	}
 	if c.Templates.ScaffoldPRBody == "" {
-		c.Templates.ScaffoldPRBody = `[registry](https://github.com/aquaproj/aqua-registry/tree/main/pkgs/{{.PackageName}}) | [repository](https://github.com/{{.RepoOwner}}/{{.RepoName}})
+		c.Templates.ScaffoldPRBody = `[registry]({{.ServerURL}}/{{.RegistryRepoOwner}}/{{.RegistryRepoName}}/tree/{{.BaseBranch}}/pkgs/{{.PackageName}}){{if .RepoOwner}} | [repository]({{.ServerURL}}/{{.RepoOwner}}/{{.RepoName}}){{end}}
 
 The command "cmdx s {{.PackageName}}" was run.
 
+{{if .ScaffoldChanges}}## Changes
+
+{{.ScaffoldChanges}}
+{{end}}
 This pull request was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
 	}
 
+	if c.Templates.ArchivedPRTitle == "" {
+		c.Templates.ArchivedPRTitle = "chore({{.PackageName}}): remove the package because the repository was archived"
+	}
+	if c.Templates.ArchivedPRBody == "" {
+		c.Templates.ArchivedPRBody = `The GitHub Repository [{{.RepoOwner}}/{{.RepoName}}]({{.ServerURL}}/{{.RepoOwner}}/{{.RepoName}}) of the package "{{.PackageName}}" was archived.
+This pull request removes the package from the registry.
+If the package should be kept, please close this pull request.
+
+This pull request was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
+	}
+
+	if c.Templates.RepositoryGoneIssueTitle == "" {
+		c.Templates.RepositoryGoneIssueTitle = "{{.PackageName}}: the repository is gone"
+	}
+	if c.Templates.RepositoryGoneIssueBody == "" {
+		c.Templates.RepositoryGoneIssueBody = `The GitHub Repository [{{.RepoOwner}}/{{.RepoName}}]({{.ServerURL}}/{{.RepoOwner}}/{{.RepoName}}) of the package "{{.PackageName}}" isn't found.
+It may have been deleted or made private.
+
+aqua-registry-updater skips the package until the repository becomes accessible again or the package is removed from the registry.
+
+This issue was created by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater).`
+	}
+
 	c.compiledTemplates = &CompiledTemplates{}
 
 	prTitle, err := compileTemplate(c.Templates.PRTitle)
@@ -125,5 +271,29 @@ This pull request was created by [aqua-registry-updater](https://github.com/aqua
 	}
 	c.compiledTemplates.ScaffoldPRBody = scaffoldPRBody
 
+	archivedPRTitle, err := compileTemplate(c.Templates.ArchivedPRTitle)
+	if err != nil {
+		return fmt.Errorf("compile a template archived_pr_title: %w", err)
+	}
+	c.compiledTemplates.ArchivedPRTitle = archivedPRTitle
+
+	archivedPRBody, err := compileTemplate(c.Templates.ArchivedPRBody)
+	if err != nil {
+		return fmt.Errorf("compile a template archived_pr_body: %w", err)
+	}
+	c.compiledTemplates.ArchivedPRBody = archivedPRBody
+
+	repositoryGoneIssueTitle, err := compileTemplate(c.Templates.RepositoryGoneIssueTitle)
+	if err != nil {
+		return fmt.Errorf("compile a template repository_gone_issue_title: %w", err)
+	}
+	c.compiledTemplates.RepositoryGoneIssueTitle = repositoryGoneIssueTitle
+
+	repositoryGoneIssueBody, err := compileTemplate(c.Templates.RepositoryGoneIssueBody)
+	if err != nil {
+		return fmt.Errorf("compile a template repository_gone_issue_body: %w", err)
+	}
+	c.compiledTemplates.RepositoryGoneIssueBody = repositoryGoneIssueBody
+
 	return nil
 }
@@ -7,17 +7,28 @@ import (
 
 	"github.com/google/go-github/v89/github"
 	"github.com/spf13/afero"
+	"golang.org/x/oauth2"
 )
 
 type ParamNew struct {
 	RepoOwner string
 	RepoName  string
+	// RateLimit must be shared with the http client of GitHub API.
+	RateLimit *RateLimit
+	URLs      *GitHubURLs
+	// TokenSource is the source of GitHub Access Tokens passed to commands such as aqua.
+	// If it's nil, commands use the environment variable GITHUB_TOKEN as is.
+	TokenSource oauth2.TokenSource
 }
 
 type Controller struct {
-	fs afero.Fs
-	// repo   RepositoriesService
+	fs     afero.Fs
+	repo   RepositoriesService
+	rates  RateLimitService
 	pull   PullRequestsService
+	issue  IssuesService
+	git    GitService
+	gql    GraphQLService
 	stdout io.Writer
 	stderr io.Writer
 	param  *ParamNew
@@ -25,12 +36,50 @@ type Controller struct {
 
 type PullRequestsService interface {
 	Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+	List(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error)
+	RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers github.ReviewersRequest) (*github.PullRequest, *github.Response, error)
 }
 
-func New(fs afero.Fs, param *ParamNew, pull PullRequestsService) *Controller {
+type RepositoriesService interface {
+	ListReleases(ctx context.Context, owner, repo string, opts *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error)
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, *github.Response, error)
+}
+
+type RateLimitService interface {
+	Get(ctx context.Context) (*github.RateLimits, *github.Response, error)
+}
+
+type IssuesService interface {
+	CreateComment(ctx context.Context, owner, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	AddLabelsToIssue(ctx context.Context, owner, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
+	AddAssignees(ctx context.Context, owner, repo string, number int, assignees []string) (*github.Issue, *github.Response, error)
+	Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	Edit(ctx context.Context, owner, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+type GitService interface {
+	DeleteRef(ctx context.Context, owner, repo, ref string) (*github.Response, error)
+	ListMatchingRefs(ctx context.Context, owner, repo, ref string) ([]*github.Reference, *github.Response, error)
+	GetCommit(ctx context.Context, owner, repo, sha string) (*github.Commit, *github.Response, error)
+	GetRef(ctx context.Context, owner, repo, ref string) (*github.Reference, *github.Response, error)
+	CreateRef(ctx context.Context, owner, repo string, ref github.CreateRef) (*github.Reference, *github.Response, error)
+	UpdateRef(ctx context.Context, owner, repo, ref string, updateRef github.UpdateRef) (*github.Reference, *github.Response, error)
+	CreateBlob(ctx context.Context, owner, repo string, blob github.Blob) (*github.Blob, *github.Response, error)
+	CreateTree(ctx context.Context, owner, repo, baseTree string, entries []*github.TreeEntry) (*github.Tree, *github.Response, error)
+	CreateCommit(ctx context.Context, owner, repo string, commit github.Commit, opts *github.CreateCommitOptions) (*github.Commit, *github.Response, error)
+}
+
+func New(fs afero.Fs, param *ParamNew, pull PullRequestsService, issue IssuesService, git GitService, gql GraphQLService, repo RepositoriesService, rates RateLimitService) *Controller {
 	return &Controller{
 		fs:     fs,
+		repo:   repo,
+		rates:  rates,
 		pull:   pull,
+		issue:  issue,
+		git:    git,
+		gql:    gql,
 		stdout: os.Stdout,
 		stderr: os.Stderr,
 		param:  param,
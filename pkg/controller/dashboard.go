@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+// DashboardConfig is the configuration of the dashboard issue.
+// The dashboard issue is pinned and rewritten at the end of each run.
+type DashboardConfig struct {
+	Enabled bool
+	Title   string
+}
+
+func (d *DashboardConfig) IsEnabled() bool {
+	return d != nil && d.Enabled
+}
+
+// maxErrorLength is the maximum length of error messages in the dashboard.
+const maxErrorLength = 200
+
+type ParamDashboard struct {
+	Data           *Data
+	OpenPRs        []*github.PullRequest
+	IgnorePackages []string
+	// Handled is the number of packages handled in the run.
+	Handled int
+}
+
+func renderDashboard(param *ParamDashboard) string {
+	b := &strings.Builder{}
+	b.WriteString("This issue is updated by [aqua-registry-updater](https://github.com/aquaproj/aqua-registry-updater) at the end of each run. Changes to this issue will be overwritten.\n\n")
+
+	b.WriteString("## Open pull requests\n\n")
+	if len(param.OpenPRs) == 0 {
+		b.WriteString("Nothing.\n")
+	}
+	for _, pr := range param.OpenPRs {
+		fmt.Fprintf(b, "- #%d\n", pr.GetNumber())
+	}
+
+	b.WriteString("\n## Failing packages\n\n")
+	failing := 0
+	for _, pkg := range param.Data.Packages {
+		if pkg.Failures == 0 {
+			continue
+		}
+		failing++
+		fmt.Fprintf(b, "- `%s` (%d consecutive failures): %s\n", pkg.Name, pkg.Failures, formatErrorMessage(pkg.Error))
+	}
+	if failing == 0 {
+		b.WriteString("Nothing.\n")
+	}
+
+	b.WriteString("\n## Skipped versions\n\nThese versions are skipped because their prefixes are different from the current versions.\n\n")
+	skipped := 0
+	for _, pkg := range param.Data.Packages {
+		if pkg.SkippedVersion == "" {
+			continue
+		}
+		skipped++
+		fmt.Fprintf(b, "- `%s`: `%s`\n", pkg.Name, pkg.SkippedVersion)
+	}
+	if skipped == 0 {
+		b.WriteString("Nothing.\n")
+	}
+
+	b.WriteString("\n## Pending transfers\n\nThese packages are skipped because their transfer pull requests or branches already exist.\n\n")
+	pending := 0
+	for _, pkg := range param.Data.Packages {
+		if pkg.PendingTransfer == nil {
+			continue
+		}
+		pending++
+		if pkg.PendingTransfer.PRNumber == 0 {
+			fmt.Fprintf(b, "- `%s` => `%s` (the branch exists but the pull request isn't found)\n", pkg.Name, pkg.PendingTransfer.NewPackageName)
+			continue
+		}
+		fmt.Fprintf(b, "- `%s` => `%s`: #%d\n", pkg.Name, pkg.PendingTransfer.NewPackageName, pkg.PendingTransfer.PRNumber)
+	}
+	if pending == 0 {
+		b.WriteString("Nothing.\n")
+	}
+
+	b.WriteString("\n## Missing repositories\n\nThese packages are skipped because their repositories were deleted or became inaccessible.\n\n")
+	gone := 0
+	for _, pkg := range param.Data.Packages {
+		if pkg.RepositoryGone == nil {
+			continue
+		}
+		gone++
+		fmt.Fprintf(b, "- `%s`: #%d\n", pkg.Name, pkg.RepositoryGone.IssueNumber)
+	}
+	if gone == 0 {
+		b.WriteString("Nothing.\n")
+	}
+
+	b.WriteString("\n## Ignored packages\n\n")
+	if len(param.IgnorePackages) == 0 {
+		b.WriteString("Nothing.\n")
+	}
+	for _, pkgName := range param.IgnorePackages {
+		fmt.Fprintf(b, "- `%s`\n", pkgName)
+	}
+
+	b.WriteString("\n## Queue\n\n")
+	if len(param.Data.Packages) != 0 {
+		fmt.Fprintf(b, "The next run starts from `%s`.\n", param.Data.Packages[0].Name)
+	}
+	fmt.Fprintf(b, "%d packages were handled in the last run. There are %d packages in total.\n", param.Handled, len(param.Data.Packages))
+
+	return truncate(b.String(), maxPRBodyLength)
+}
+
+func formatErrorMessage(msg string) string {
+	return "`" + strings.ReplaceAll(truncate(strings.Join(strings.Fields(msg), " "), maxErrorLength), "`", "'") + "`"
+}
+
+// updateDashboard creates or updates the dashboard issue.
+// The issue number is stored in data.json.
+func (c *Controller) updateDashboard(ctx context.Context, logger *slog.Logger, cfg *DashboardConfig, param *ParamDashboard) error {
+	body := renderDashboard(param)
+	if number := param.Data.DashboardIssue; number != 0 {
+		issue, resp, err := c.issue.Edit(ctx, c.param.RepoOwner, c.param.RepoName, number, &github.IssueRequest{
+			Title: new(cfg.Title),
+			Body:  new(body),
+		})
+		if err != nil {
+			if resp == nil || (resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone) {
+				return fmt.Errorf("update the dashboard issue: %w", err)
+			}
+		} else if issue.GetState() == "open" {
+			return nil
+		}
+		logger.Info("the dashboard issue was closed or deleted, so a new issue is created", "issue_number", number)
+	}
+	issue, _, err := c.issue.Create(ctx, c.param.RepoOwner, c.param.RepoName, &github.IssueRequest{
+		Title: new(cfg.Title),
+		Body:  new(body),
+	})
+	if err != nil {
+		return fmt.Errorf("create the dashboard issue: %w", err)
+	}
+	param.Data.DashboardIssue = issue.GetNumber()
+	if err := c.gql.PinIssue(ctx, issue.GetNodeID()); err != nil {
+		slogerr.WithError(logger, err).Warn("pin the dashboard issue")
+	}
+	return nil
+}
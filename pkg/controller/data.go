@@ -3,14 +3,73 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type Data struct {
 	Packages []*Package `json:"packages"`
+	// DashboardIssue is the number of the dashboard issue.
+	DashboardIssue int `json:"dashboard_issue,omitempty"`
+	// AutoMerges are times when auto-merge was enabled in the last 24 hours.
+	AutoMerges []time.Time `json:"auto_merges,omitempty"`
+}
+
+// countAutoMerges removes times older than 24 hours from AutoMerges and returns the number of the rest.
+func (d *Data) countAutoMerges(now time.Time) int {
+	autoMerges := make([]time.Time, 0, len(d.AutoMerges))
+	for _, t := range d.AutoMerges {
+		if now.Sub(t) < 24*time.Hour {
+			autoMerges = append(autoMerges, t)
+		}
+	}
+	d.AutoMerges = autoMerges
+	return len(autoMerges)
 }
 
 type Package struct {
 	Name string `json:"name"`
+	// Failures is the number of consecutive failures.
+	Failures int `json:"failures,omitempty"`
+	// Error is the error message of the last failure.
+	Error string `json:"error,omitempty"`
+	// SkippedVersion is the new version which was skipped because its prefix is different from the current version.
+	SkippedVersion string `json:"skipped_version,omitempty"`
+	// PendingTransfer is set when the package's repository was transferred but the transfer pull request hasn't been merged yet.
+	PendingTransfer *PendingTransfer `json:"pending_transfer,omitempty"`
+	// RepositoryGone is set when the package's repository was deleted or became inaccessible.
+	// The package is skipped while it's set.
+	RepositoryGone *RepositoryGone `json:"repository_gone,omitempty"`
+	// ArchivedPRNumber is the number of the pull request removing the package because the repository was archived.
+	// If the pull request is closed without being merged, the package is kept and the pull request isn't created again until the repository is unarchived.
+	ArchivedPRNumber int `json:"archived_pr_number,omitempty"`
+	// ScaffoldedAt is the time when the package was re-scaffolded last.
+	ScaffoldedAt time.Time `json:"scaffolded_at,omitzero"`
+}
+
+// isScaffoldDue returns true if the interval has passed since the package was re-scaffolded last.
+func (p *Package) isScaffoldDue(now time.Time, interval time.Duration) bool {
+	return now.Sub(p.ScaffoldedAt) >= interval
+}
+
+type RepositoryGone struct {
+	// IssueNumber is the number of the issue tracking the package.
+	IssueNumber int `json:"issue_number"`
+}
+
+type PendingTransfer struct {
+	NewPackageName string `json:"new_package_name"`
+	// PRNumber is the number of the transfer pull request. It's 0 if the branch exists but the pull request isn't found.
+	PRNumber int `json:"pr_number,omitempty"`
+}
+
+func (p *Package) setResult(err error) {
+	if err == nil {
+		p.Failures = 0
+		p.Error = ""
+		return
+	}
+	p.Failures++
+	p.Error = err.Error()
 }
 
 func (c *Controller) writeData(path string, data *Data) error {
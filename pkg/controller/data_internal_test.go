@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPackage_isScaffoldDue(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	interval := 90 * 24 * time.Hour
+	data := []struct {
+		name string
+		pkg  *Package
+		exp  bool
+	}{
+		{
+			name: "never scaffolded",
+			pkg:  &Package{},
+			exp:  true,
+		},
+		{
+			name: "scaffolded recently",
+			pkg:  &Package{ScaffoldedAt: now.Add(-24 * time.Hour)},
+		},
+		{
+			name: "interval passed",
+			pkg:  &Package{ScaffoldedAt: now.Add(-interval)},
+			exp:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if got := d.pkg.isScaffoldDue(now, interval); got != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,10 +11,12 @@ import (
 	"github.com/aquaproj/registry-tool/pkg/checkrepo"
 	genrg "github.com/aquaproj/registry-tool/pkg/generate-registry"
 	"github.com/aquaproj/registry-tool/pkg/mv"
+	"github.com/google/go-github/v89/github"
 )
 
-func (c *Controller) fixRedirect(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config) (f bool, e error) {
+func (c *Controller) fixRedirect(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, state *runState) (f bool, e error) {
 	httpClient := &http.Client{
+		Transport: c.param.URLs.serverTransport(),
 		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -23,25 +26,79 @@ func (c *Controller) fixRedirect(ctx context.Context, logger *slog.Logger, pkg *
 		return false, fmt.Errorf("check if the repository was transferred: %w", err)
 	}
 	if redirect == nil {
+		pkg.PendingTransfer = nil
 		return false, nil
 	}
 	logger.Info("the package's repository was transferred", "repo_owner", redirect.NewRepoOwner, "repo_name", redirect.NewRepoName)
-	if err := mv.Move(ctx, c.fs, pkg.Name, redirect.NewPackageName); err != nil {
-		return false, fmt.Errorf("rename a package: %w", err)
+	branch := branchPrefix + "transfer-" + pkg.Name
+	if pr := state.findPR(branch); pr != nil {
+		logger.Info("skip the transfer because the pull request already exists", "pr_number", pr.GetNumber())
+		pkg.PendingTransfer = &PendingTransfer{
+			NewPackageName: redirect.NewPackageName,
+			PRNumber:       pr.GetNumber(),
+		}
+		return true, nil
 	}
-	if err := genrg.GenerateRegistry(ctx); err != nil {
-		return false, fmt.Errorf("update registry.yaml: %w", err)
+	if ok, err := c.checkBranch(ctx, branch); err != nil {
+		return false, fmt.Errorf("check a branch: %w", err)
+	} else if ok {
+		logger.Info("skip the transfer because the branch already exists", "branch", branch)
+		pkg.PendingTransfer = &PendingTransfer{
+			NewPackageName: redirect.NewPackageName,
+		}
+		return true, nil
 	}
-	defer func() {
-		if err := c.clean(ctx, redirect.NewPackageName); err != nil {
-			if e == nil {
+	pkg.PendingTransfer = nil
+	// If the new package already exists, the old package is removed instead of being moved.
+	// Otherwise mv.Move would overwrite the new package and clean would remove it.
+	duplicated := c.pkgExists(redirect.NewPackageName)
+	if duplicated {
+		logger.Warn("the new package already exists, so the old package is removed", "new_package_name", redirect.NewPackageName)
+		defer func() {
+			if err := c.exec(ctx, "git", "checkout", "--", "."); err != nil && e == nil {
 				e = err
 			}
+		}()
+		oldPkgDir := filepath.Join("pkgs", filepath.FromSlash(pkg.Name))
+		for _, file := range []string{"pkg.yaml", "registry.yaml"} {
+			if err := c.fs.Remove(filepath.Join(oldPkgDir, file)); err != nil {
+				return false, fmt.Errorf("remove old %s: %w", file, err)
+			}
+		}
+	} else {
+		if err := mv.Move(ctx, c.fs, pkg.Name, redirect.NewPackageName); err != nil {
+			return false, fmt.Errorf("rename a package: %w", err)
+		}
+		defer func() {
+			if err := c.clean(ctx, redirect.NewPackageName); err != nil {
+				if e == nil {
+					e = err
+				}
+			}
+		}()
+	}
+	alias := ""
+	if cfg.Transfer.AddAlias {
+		added, err := c.addAliasToPackage(redirect.NewPackageName, pkg.Name)
+		if err != nil {
+			return false, err
 		}
-	}()
-	if err := c.createFixRedirectPR(ctx, pkg.Name, cfg, redirect); err != nil {
+		if added {
+			alias = pkg.Name
+		}
+	}
+	if err := genrg.GenerateRegistry(ctx); err != nil {
+		return false, fmt.Errorf("update registry.yaml: %w", err)
+	}
+	pr, err := c.createFixRedirectPR(ctx, logger, pkg.Name, cfg, redirect, branch, alias, duplicated)
+	if errors.Is(err, errNothingToCommit) {
+		logger.Info("files aren't changed from the base branch, so a pull request isn't created")
+		return false, nil
+	}
+	if err != nil {
 		return false, err
 	}
+	state.openPRs = append(state.openPRs, pr)
 	return true, nil
 }
 
@@ -58,44 +115,56 @@ func (c *Controller) clean(ctx context.Context, pkgName string) error {
 	return nil
 }
 
-func (c *Controller) createFixRedirectPR(ctx context.Context, pkgName string, cfg *Config, redirect *checkrepo.Redirect) error {
+func (c *Controller) createFixRedirectPR(ctx context.Context, logger *slog.Logger, pkgName string, cfg *Config, redirect *checkrepo.Redirect, branch, alias string, duplicated bool) (*github.PullRequest, error) {
 	paramTemplates := &ParamTemplates{
+		ServerURL:      c.param.URLs.ServerURL,
 		PackageName:    pkgName,
 		RepoOwner:      redirect.RepoOwner,
 		RepoName:       redirect.RepoName,
 		NewRepoOwner:   redirect.NewRepoOwner,
 		NewRepoName:    redirect.NewRepoName,
 		NewPackageName: redirect.NewPackageName,
+		Alias:          alias,
+		Duplicated:     duplicated,
 	}
 
 	prTitle, err := renderTemplate(cfg.compiledTemplates.TransferPRTitle, paramTemplates)
 	if err != nil {
-		return fmt.Errorf("render a template pr_title: %w", err)
+		return nil, fmt.Errorf("render a template pr_title: %w", err)
 	}
 
 	prBody, err := renderTemplate(cfg.compiledTemplates.TransferPRBody, paramTemplates)
 	if err != nil {
-		return fmt.Errorf("render a template pr_body: %w", err)
+		return nil, fmt.Errorf("render a template pr_body: %w", err)
 	}
 
 	pkgDir := filepath.Join("pkgs", filepath.FromSlash(redirect.NewPackageName))
 	oldPkgDir := filepath.Join("pkgs", filepath.FromSlash(pkgName))
-	branch := "aqua-registry-updater-transfer-" + pkgName
-	if err := c.exec(ctx, "ghcp", "commit",
-		"-r", fmt.Sprintf("%s/%s", c.param.RepoOwner, c.param.RepoName),
-		"-b", branch, "-m", prTitle,
-		"-d", fmt.Sprintf("%s,%s", filepath.Join(oldPkgDir, "pkg.yaml"), filepath.Join(oldPkgDir, "registry.yaml")),
-		"registry.yaml",
-		filepath.Join(pkgDir, "registry.yaml"),
-		filepath.Join(pkgDir, "pkg.yaml")); err != nil {
-		return fmt.Errorf("create a branch: %w", err)
-	}
-	if _, err := c.createPR(ctx, &ParamCreatePR{
+	prCfg := cfg.PullRequest.ForPackage(pkgName)
+	if _, err := c.commit(ctx, &ParamCommit{
+		Base:    prCfg.Base,
+		Branch:  branch,
+		Message: prTitle,
+		Files: []string{
+			"registry.yaml",
+			filepath.Join(pkgDir, "registry.yaml"),
+			filepath.Join(pkgDir, "pkg.yaml"),
+		},
+		Deletions: []string{
+			filepath.Join(oldPkgDir, "pkg.yaml"),
+			filepath.Join(oldPkgDir, "registry.yaml"),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create a branch: %w", err)
+	}
+	pr, err := c.createPR(ctx, logger, &ParamCreatePR{
 		Title:  prTitle,
 		Branch: branch,
 		Body:   prBody,
-	}); err != nil {
-		return fmt.Errorf("create a pull request: %w", err)
+		Config: prCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create a pull request: %w", err)
 	}
-	return nil
+	return pr, nil
 }
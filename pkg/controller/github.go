@@ -3,38 +3,138 @@ package controller
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
 
 	"github.com/google/go-github/v89/github"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
 	"golang.org/x/oauth2"
 )
 
-func NewGitHub(ctx context.Context, token string) (*github.Client, error) {
-	v3, err := github.NewClient(github.WithHTTPClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	))))
+func NewHTTPClient(ctx context.Context, ts oauth2.TokenSource, rateLimit *RateLimit) *http.Client {
+	client := oauth2.NewClient(ctx, ts)
+	client.Transport = rateLimit.Transport(client.Transport)
+	return client
+}
+
+func NewGitHub(httpClient *http.Client, urls *GitHubURLs) (*github.Client, error) {
+	opts := []github.ClientOptionsFunc{github.WithHTTPClient(httpClient)}
+	if urls.IsEnterprise() {
+		opts = append(opts, github.WithEnterpriseURLs(urls.BaseURL, urls.UploadURL))
+	}
+	v3, err := github.NewClient(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("create a GitHub client: %w", err)
 	}
 	return v3, nil
 }
 
+// PullRequestConfig is the configuration of pull requests created by aqua-registry-updater.
+type PullRequestConfig struct {
+	Base          string
+	Labels        []string
+	Assignees     []string
+	Reviewers     []string
+	TeamReviewers []string `yaml:"team_reviewers"`
+	Draft         bool
+	// Overrides overrides the configuration per package.
+	Overrides []*PullRequestOverride
+}
+
+type PullRequestOverride struct {
+	Packages      []string
+	Base          string
+	Labels        []string
+	Assignees     []string
+	Reviewers     []string
+	TeamReviewers []string `yaml:"team_reviewers"`
+	Draft         *bool
+}
+
+// ForPackage returns the configuration applied to the package.
+// Overrides are applied in order, so later overrides take precedence.
+func (p *PullRequestConfig) ForPackage(pkgName string) *PullRequestConfig {
+	cfg := &PullRequestConfig{
+		Base:          p.Base,
+		Labels:        p.Labels,
+		Assignees:     p.Assignees,
+		Reviewers:     p.Reviewers,
+		TeamReviewers: p.TeamReviewers,
+		Draft:         p.Draft,
+	}
+	for _, override := range p.Overrides {
+		if !slices.Contains(override.Packages, pkgName) {
+			continue
+		}
+		if override.Base != "" {
+			cfg.Base = override.Base
+		}
+		if override.Labels != nil {
+			cfg.Labels = override.Labels
+		}
+		if override.Assignees != nil {
+			cfg.Assignees = override.Assignees
+		}
+		if override.Reviewers != nil {
+			cfg.Reviewers = override.Reviewers
+		}
+		if override.TeamReviewers != nil {
+			cfg.TeamReviewers = override.TeamReviewers
+		}
+		if override.Draft != nil {
+			cfg.Draft = *override.Draft
+		}
+	}
+	return cfg
+}
+
 type ParamCreatePR struct {
 	NewVersion     string
 	CurrentVersion string
 	Title          string
 	Branch         string
 	Body           string
+	Config         *PullRequestConfig
 }
 
-func (c *Controller) createPR(ctx context.Context, param *ParamCreatePR) (int, error) {
+func (c *Controller) createPR(ctx context.Context, logger *slog.Logger, param *ParamCreatePR) (*github.PullRequest, error) {
 	pr, _, err := c.pull.Create(ctx, c.param.RepoOwner, c.param.RepoName, &github.NewPullRequest{
 		Head:  new(param.Branch),
-		Base:  new("main"),
+		Base:  new(param.Config.Base),
 		Title: new(param.Title),
 		Body:  new(param.Body),
+		Draft: new(param.Config.Draft),
 	})
 	if err != nil {
-		return 0, fmt.Errorf("create a pull request: %w", err)
+		return nil, fmt.Errorf("create a pull request: %w", err)
+	}
+	// The pull request has already been created, so failures are logged instead of being returned.
+	logger = logger.With("pr_number", pr.GetNumber())
+	if err := c.editPR(ctx, pr.GetNumber(), param.Config); err != nil {
+		slogerr.WithError(logger, err).Error("set labels, assignees, and reviewers to a pull request")
+	}
+	return pr, nil
+}
+
+func (c *Controller) editPR(ctx context.Context, number int, cfg *PullRequestConfig) error {
+	if len(cfg.Labels) != 0 {
+		if _, _, err := c.issue.AddLabelsToIssue(ctx, c.param.RepoOwner, c.param.RepoName, number, cfg.Labels); err != nil {
+			return fmt.Errorf("add labels to a pull request: %w", err)
+		}
+	}
+	if len(cfg.Assignees) != 0 {
+		if _, _, err := c.issue.AddAssignees(ctx, c.param.RepoOwner, c.param.RepoName, number, cfg.Assignees); err != nil {
+			return fmt.Errorf("add assignees to a pull request: %w", err)
+		}
+	}
+	if len(cfg.Reviewers) != 0 || len(cfg.TeamReviewers) != 0 {
+		if _, _, err := c.pull.RequestReviewers(ctx, c.param.RepoOwner, c.param.RepoName, number, github.ReviewersRequest{
+			Reviewers:     cfg.Reviewers,
+			TeamReviewers: cfg.TeamReviewers,
+		}); err != nil {
+			return fmt.Errorf("request reviews: %w", err)
+		}
 	}
-	return pr.GetNumber(), nil
+	return nil
 }
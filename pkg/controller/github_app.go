@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+	"golang.org/x/oauth2"
+)
+
+// ParamGitHubApp is the parameter to authenticate as a GitHub App installation.
+type ParamGitHubApp struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyFile string
+	URLs           *GitHubURLs
+}
+
+// NewTokenSource returns a token source of GitHub Access Tokens.
+// If app is nil, token is used as is.
+// Otherwise installation access tokens of the GitHub App are created and refreshed before they expire.
+func NewTokenSource(ctx context.Context, token string, app *ParamGitHubApp) (oauth2.TokenSource, error) {
+	if app == nil {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	}
+	b, err := os.ReadFile(app.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read a private key file of the GitHub App: %w", err)
+	}
+	key, err := parsePrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("parse a private key of the GitHub App: %w", err)
+	}
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{
+		ctx:            ctx,
+		appID:          app.AppID,
+		installationID: app.InstallationID,
+		key:            key,
+		urls:           app.URLs,
+	}), nil
+}
+
+func parsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("the private key isn't PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse a private key: %w", err)
+	}
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key isn't a RSA private key")
+	}
+	return key, nil
+}
+
+type appTokenSource struct {
+	ctx            context.Context //nolint:containedctx
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	urls           *GitHubURLs
+}
+
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.signJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	opts := []github.ClientOptionsFunc{github.WithAuthToken(jwt)}
+	if s.urls.IsEnterprise() {
+		opts = append(opts, github.WithEnterpriseURLs(s.urls.BaseURL, s.urls.UploadURL))
+	}
+	client, err := github.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create a GitHub client: %w", err)
+	}
+	token, _, err := client.Apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create an installation access token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// signJWT creates a JSON Web Token to authenticate as the GitHub App.
+// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (s *appTokenSource) signJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal a JWT header as JSON: %w", err)
+	}
+	payload, err := json.Marshal(map[string]any{
+		// Allow clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		// The maximum expiration time is 10 minutes
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal a JWT payload as JSON: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign a JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
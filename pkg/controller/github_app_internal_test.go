@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parsePrivateKey(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []struct {
+		name  string
+		key   []byte
+		isErr bool
+	}{
+		{
+			name: "pkcs1",
+			key:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		{
+			name: "pkcs8",
+			key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			name:  "not pem",
+			key:   []byte("foo"),
+			isErr: true,
+		},
+		{
+			name:  "broken key",
+			key:   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("foo")}),
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			k, err := parsePrivateKey(d.key)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if !k.Equal(key) {
+				t.Fatal("the parsed key is different from the original key")
+			}
+		})
+	}
+}
+
+func Test_appTokenSource_signJWT(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	s := &appTokenSource{appID: 123, key: key}
+	jwt, err := s.signJWT(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 { //nolint:mnd
+		t.Fatalf("JWT must have 3 parts, got %d", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("verify the signature: %v", err)
+	}
+	header := map[string]string{}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		t.Fatal(err)
+	}
+	if header["alg"] != "RS256" || header["typ"] != "JWT" {
+		t.Fatalf("the header is wrong: %v", header)
+	}
+	claims := struct {
+		IAT int64  `json:"iat"`
+		EXP int64  `json:"exp"`
+		ISS string `json:"iss"`
+	}{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.IAT != now.Add(-time.Minute).Unix() {
+		t.Fatalf("iat is wrong: %d", claims.IAT)
+	}
+	if claims.EXP != now.Add(9*time.Minute).Unix() {
+		t.Fatalf("exp is wrong: %d", claims.EXP)
+	}
+	if claims.ISS != "123" {
+		t.Fatalf("iss is wrong: %s", claims.ISS)
+	}
+}
+
+func decodeJWTPart(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	return json.Unmarshal(b, v) //nolint:wrapcheck
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPullRequestConfig_ForPackage(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	base := func(overrides ...*PullRequestOverride) *PullRequestConfig {
+		return &PullRequestConfig{
+			Base:          "main",
+			Labels:        []string{"update"},
+			Assignees:     []string{"alice"},
+			Reviewers:     []string{"bob"},
+			TeamReviewers: []string{"maintainers"},
+			Draft:         true,
+			Overrides:     overrides,
+		}
+	}
+	data := []struct {
+		name string
+		cfg  *PullRequestConfig
+		exp  *PullRequestConfig
+	}{
+		{
+			name: "no override",
+			cfg:  base(),
+			exp: &PullRequestConfig{
+				Base:          "main",
+				Labels:        []string{"update"},
+				Assignees:     []string{"alice"},
+				Reviewers:     []string{"bob"},
+				TeamReviewers: []string{"maintainers"},
+				Draft:         true,
+			},
+		},
+		{
+			name: "the override doesn't match",
+			cfg: base(&PullRequestOverride{
+				Packages: []string{"foo/baz"},
+				Base:     "develop",
+				Labels:   []string{"baz"},
+				Draft:    new(false),
+			}),
+			exp: &PullRequestConfig{
+				Base:          "main",
+				Labels:        []string{"update"},
+				Assignees:     []string{"alice"},
+				Reviewers:     []string{"bob"},
+				TeamReviewers: []string{"maintainers"},
+				Draft:         true,
+			},
+		},
+		{
+			name: "nil fields are inherited",
+			cfg: base(&PullRequestOverride{
+				Packages: []string{"foo/bar"},
+				Labels:   []string{"foo"},
+			}),
+			exp: &PullRequestConfig{
+				Base:          "main",
+				Labels:        []string{"foo"},
+				Assignees:     []string{"alice"},
+				Reviewers:     []string{"bob"},
+				TeamReviewers: []string{"maintainers"},
+				Draft:         true,
+			},
+		},
+		{
+			name: "empty slices and false clear the configuration",
+			cfg: base(&PullRequestOverride{
+				Packages:      []string{"foo/bar"},
+				Labels:        []string{},
+				Assignees:     []string{},
+				Reviewers:     []string{},
+				TeamReviewers: []string{},
+				Draft:         new(false),
+			}),
+			exp: &PullRequestConfig{
+				Base:          "main",
+				Labels:        []string{},
+				Assignees:     []string{},
+				Reviewers:     []string{},
+				TeamReviewers: []string{},
+			},
+		},
+		{
+			name: "later overrides take precedence",
+			cfg: base(
+				&PullRequestOverride{
+					Packages:  []string{"foo/bar"},
+					Base:      "develop",
+					Labels:    []string{"foo"},
+					Assignees: []string{"carol"},
+					Draft:     new(false),
+				},
+				&PullRequestOverride{
+					Packages: []string{"foo/baz", "foo/bar"},
+					Base:     "release",
+					Labels:   []string{"bar"},
+				},
+			),
+			exp: &PullRequestConfig{
+				Base:          "release",
+				Labels:        []string{"bar"},
+				Assignees:     []string{"carol"},
+				Reviewers:     []string{"bob"},
+				TeamReviewers: []string{"maintainers"},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := d.cfg.ForPackage("foo/bar")
+			if !reflect.DeepEqual(cfg, d.exp) {
+				t.Fatalf("wanted %+v, got %+v", d.exp, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultServerURL  = "https://github.com"
+	defaultGraphQLURL = "https://api.github.com/graphql"
+)
+
+// GitHubURLs are URLs of GitHub.
+// They are changed to use GitHub Enterprise Server.
+type GitHubURLs struct {
+	// ServerURL is the URL of the web UI such as https://github.com.
+	ServerURL string
+	// BaseURL is the URL of REST API. It's empty for github.com.
+	BaseURL string
+	// UploadURL is the upload URL of REST API. It's empty for github.com.
+	UploadURL  string
+	GraphQLURL string
+}
+
+// NewGitHubURLs returns URLs of GitHub.
+// If baseURL is empty, URLs of github.com are returned.
+// Otherwise baseURL is the URL of GitHub Enterprise Server such as https://ghes.example.com/api/v3/ ,
+// and uploadURL defaults to the server URL.
+func NewGitHubURLs(baseURL, uploadURL string) (*GitHubURLs, error) {
+	if baseURL == "" {
+		return &GitHubURLs{
+			ServerURL:  defaultServerURL,
+			GraphQLURL: defaultGraphQLURL,
+		}, nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse a base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base URL must be an absolute URL: %s", baseURL)
+	}
+	serverURL := u.Scheme + "://" + u.Host
+	if uploadURL == "" {
+		uploadURL = serverURL + "/"
+	}
+	return &GitHubURLs{
+		ServerURL:  serverURL,
+		BaseURL:    baseURL,
+		UploadURL:  uploadURL,
+		GraphQLURL: serverURL + "/api/graphql",
+	}, nil
+}
+
+// IsEnterprise returns true if URLs are of GitHub Enterprise Server.
+func (u *GitHubURLs) IsEnterprise() bool {
+	return u != nil && u.BaseURL != ""
+}
+
+// serverTransport returns a http.RoundTripper which sends requests to github.com to the server.
+// This is used for libraries which assume github.com.
+// For github.com, nil is returned so that the default transport is used.
+func (u *GitHubURLs) serverTransport() http.RoundTripper {
+	if !u.IsEnterprise() {
+		return nil
+	}
+	server, err := url.Parse(u.ServerURL)
+	if err != nil {
+		return nil
+	}
+	return &serverTransport{
+		base:   http.DefaultTransport,
+		server: server,
+	}
+}
+
+type serverTransport struct {
+	base   http.RoundTripper
+	server *url.URL
+}
+
+func (t *serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "github.com" {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = t.server.Scheme
+		req.URL.Host = t.server.Host
+		req.Host = t.server.Host
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	// Rewrite redirects to the server so that they can be parsed as github.com's URLs
+	if loc := resp.Header.Get("Location"); loc != "" {
+		resp.Header.Set("Location", strings.Replace(loc, t.server.Scheme+"://"+t.server.Host, defaultServerURL, 1))
+	}
+	return resp, nil
+}
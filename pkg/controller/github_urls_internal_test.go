@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestNewGitHubURLs(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name      string
+		baseURL   string
+		uploadURL string
+		exp       *GitHubURLs
+		isErr     bool
+	}{
+		{
+			name: "github.com",
+			exp: &GitHubURLs{
+				ServerURL:  "https://github.com",
+				GraphQLURL: "https://api.github.com/graphql",
+			},
+		},
+		{
+			name:    "ghes",
+			baseURL: "https://ghes.example.com/api/v3/",
+			exp: &GitHubURLs{
+				ServerURL:  "https://ghes.example.com",
+				BaseURL:    "https://ghes.example.com/api/v3/",
+				UploadURL:  "https://ghes.example.com/",
+				GraphQLURL: "https://ghes.example.com/api/graphql",
+			},
+		},
+		{
+			name:      "ghes with upload url",
+			baseURL:   "https://ghes.example.com/api/v3/",
+			uploadURL: "https://uploads.ghes.example.com/",
+			exp: &GitHubURLs{
+				ServerURL:  "https://ghes.example.com",
+				BaseURL:    "https://ghes.example.com/api/v3/",
+				UploadURL:  "https://uploads.ghes.example.com/",
+				GraphQLURL: "https://ghes.example.com/api/graphql",
+			},
+		},
+		{
+			name:    "relative url",
+			baseURL: "ghes.example.com/api/v3/",
+			isErr:   true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			urls, err := NewGitHubURLs(d.baseURL, d.uploadURL)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if *urls != *d.exp {
+				t.Fatalf("wanted %+v, got %+v", d.exp, urls)
+			}
+			if urls.IsEnterprise() != (d.baseURL != "") {
+				t.Fatalf("IsEnterprise must be %v", d.baseURL != "")
+			}
+		})
+	}
+}
+
+func Test_serverTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+	hosts := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.Host)
+		w.Header().Set("Location", "http://"+r.Host+"/foo/bar/releases/download/v1.0.0/bar.tar.gz")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer srv.Close()
+	server, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &serverTransport{base: http.DefaultTransport, server: server}
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://github.com/foo/bar/releases/latest", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if len(hosts) != 1 || hosts[0] != server.Host {
+		t.Fatalf("the request must be sent to %s, got %v", server.Host, hosts)
+	}
+	if req.URL.Host != "github.com" {
+		t.Fatalf("the caller's request must not be modified, got %s", req.URL.Host)
+	}
+	if loc := resp.Header.Get("Location"); loc != "https://github.com/foo/bar/releases/download/v1.0.0/bar.tar.gz" {
+		t.Fatalf("Location must be rewritten to github.com, got %s", loc)
+	}
+	if (&GitHubURLs{ServerURL: defaultServerURL}).serverTransport() != nil {
+		t.Fatal("the transport must be nil for github.com")
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type GraphQLService interface {
+	EnablePullRequestAutoMerge(ctx context.Context, pullRequestID, mergeMethod string) error
+	PinIssue(ctx context.Context, issueID string) error
+}
+
+// GraphQL is a minimal client of GitHub GraphQL API.
+type GraphQL struct {
+	client   *http.Client
+	endpoint string
+}
+
+func NewGraphQL(client *http.Client, endpoint string) *GraphQL {
+	return &GraphQL{
+		client:   client,
+		endpoint: endpoint,
+	}
+}
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Errors []*graphQLError `json:"errors"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+func (g *GraphQL) do(ctx context.Context, query string, variables map[string]any) error {
+	b, err := json.Marshal(&graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal a GraphQL request as JSON: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send a http request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL API returned an unexpected status code: %d", resp.StatusCode)
+	}
+	body := &graphQLResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return fmt.Errorf("decode a GraphQL response as JSON: %w", err)
+	}
+	if len(body.Errors) != 0 {
+		msgs := make([]string, len(body.Errors))
+		for i, e := range body.Errors {
+			msgs[i] = e.Message
+		}
+		return errors.New(strings.Join(msgs, ", "))
+	}
+	return nil
+}
+
+const enablePullRequestAutoMergeMutation = `mutation($pullRequestId: ID!, $mergeMethod: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $pullRequestId, mergeMethod: $mergeMethod}) {
+    clientMutationId
+  }
+}`
+
+// EnablePullRequestAutoMerge enables auto-merge of a pull request.
+// pullRequestID is the node id of the pull request.
+// mergeMethod is one of "squash", "merge", and "rebase".
+func (g *GraphQL) EnablePullRequestAutoMerge(ctx context.Context, pullRequestID, mergeMethod string) error {
+	if err := g.do(ctx, enablePullRequestAutoMergeMutation, map[string]any{
+		"pullRequestId": pullRequestID,
+		"mergeMethod":   strings.ToUpper(mergeMethod),
+	}); err != nil {
+		return fmt.Errorf("enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+const pinIssueMutation = `mutation($issueId: ID!) {
+  pinIssue(input: {issueId: $issueId}) {
+    clientMutationId
+  }
+}`
+
+// PinIssue pins an issue.
+// issueID is the node id of the issue.
+func (g *GraphQL) PinIssue(ctx context.Context, issueID string) error {
+	if err := g.do(ctx, pinIssueMutation, map[string]any{
+		"issueId": issueID,
+	}); err != nil {
+		return fmt.Errorf("pin an issue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGraphQL_EnablePullRequestAutoMerge(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name        string
+		mergeMethod string
+		status      int
+		resp        string
+		isErr       bool
+	}{
+		{
+			name:        "normal",
+			mergeMethod: "squash",
+			status:      http.StatusOK,
+			resp:        `{"data":{"enablePullRequestAutoMerge":{"clientMutationId":null}}}`,
+		},
+		{
+			name:        "rebase",
+			mergeMethod: "rebase",
+			status:      http.StatusOK,
+			resp:        `{"data":{"enablePullRequestAutoMerge":{"clientMutationId":null}}}`,
+		},
+		{
+			name:        "graphql error",
+			mergeMethod: "squash",
+			status:      http.StatusOK,
+			resp:        `{"data":null,"errors":[{"message":"Pull request is in clean status"}]}`,
+			isErr:       true,
+		},
+		{
+			name:        "unauthorized",
+			mergeMethod: "squash",
+			status:      http.StatusUnauthorized,
+			resp:        `{"message":"Bad credentials"}`,
+			isErr:       true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			var req graphQLRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(d.status)
+				if _, err := w.Write([]byte(d.resp)); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer srv.Close()
+			gql := NewGraphQL(srv.Client(), srv.URL)
+			if err := gql.EnablePullRequestAutoMerge(t.Context(), "PR_xxx", d.mergeMethod); err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if id := req.Variables["pullRequestId"]; id != "PR_xxx" {
+				t.Fatalf("pullRequestId: wanted PR_xxx, got %v", id)
+			}
+			if method, want := req.Variables["mergeMethod"], map[string]string{"squash": "SQUASH", "rebase": "REBASE"}[d.mergeMethod]; method != want {
+				t.Fatalf("mergeMethod: wanted %v, got %v", want, method)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+const branchPrefix = "aqua-registry-updater-"
+
+// runState is the state shared by packages handled in a run.
+type runState struct {
+	// openPRs is the list of open pull requests created by aqua-registry-updater.
+	openPRs []*github.PullRequest
+	data    *Data
+}
+
+// listOpenPRs lists open pull requests created by aqua-registry-updater.
+func (c *Controller) listOpenPRs(ctx context.Context) ([]*github.PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State: "open",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	fullName := c.param.RepoOwner + "/" + c.param.RepoName
+	prs := []*github.PullRequest{}
+	for {
+		arr, resp, err := c.pull.List(ctx, c.param.RepoOwner, c.param.RepoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list open pull requests: %w", err)
+		}
+		for _, pr := range arr {
+			head := pr.GetHead()
+			if head.GetRepo().GetFullName() != fullName {
+				continue
+			}
+			if !strings.HasPrefix(head.GetRef(), branchPrefix) {
+				continue
+			}
+			prs = append(prs, pr)
+		}
+		if resp.NextPage == 0 {
+			return prs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (c *Controller) pkgExists(pkgName string) bool {
+	_, err := c.fs.Stat(filepath.Join("pkgs", filepath.FromSlash(pkgName), "pkg.yaml"))
+	return err == nil
+}
+
+// parseUpdateBranch parses a branch created by handlePackage and returns the package name and the version.
+// Both package names and versions can contain "-", so the longest existing package name is chosen.
+func parseUpdateBranch(branch string, exists func(pkgName string) bool) (string, string, bool) {
+	s, ok := strings.CutPrefix(branch, branchPrefix)
+	if !ok {
+		return "", "", false
+	}
+	for i := len(s) - 2; i > 0; i-- {
+		if s[i] != '-' {
+			continue
+		}
+		if exists(s[:i]) {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// findPR returns an open pull request of the branch.
+// If the pull request isn't found, it returns nil.
+func (s *runState) findPR(branch string) *github.PullRequest {
+	for _, pr := range s.openPRs {
+		if pr.GetHead().GetRef() == branch {
+			return pr
+		}
+	}
+	return nil
+}
+
+// updatePRs returns open pull requests updating the given package.
+func (c *Controller) updatePRs(state *runState, pkgName string) []*github.PullRequest {
+	prs := []*github.PullRequest{}
+	for _, pr := range state.openPRs {
+		name, _, ok := parseUpdateBranch(pr.GetHead().GetRef(), c.pkgExists)
+		if ok && name == pkgName {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+func (c *Controller) closePR(ctx context.Context, state *runState, number int, comment string) error {
+	if _, _, err := c.issue.CreateComment(ctx, c.param.RepoOwner, c.param.RepoName, number, &github.IssueComment{
+		Body: new(comment),
+	}); err != nil {
+		return fmt.Errorf("post a comment to a pull request: %w", err)
+	}
+	if _, _, err := c.pull.Edit(ctx, c.param.RepoOwner, c.param.RepoName, number, &github.PullRequest{
+		State: new("closed"),
+	}); err != nil {
+		return fmt.Errorf("close a pull request: %w", err)
+	}
+	prs := make([]*github.PullRequest, 0, len(state.openPRs))
+	for _, pr := range state.openPRs {
+		if pr.GetNumber() != number {
+			prs = append(prs, pr)
+		}
+	}
+	state.openPRs = prs
+	return nil
+}
+
+// closeSupersededPRs closes pull requests superseded by the new pull request.
+func (c *Controller) closeSupersededPRs(ctx context.Context, logger *slog.Logger, state *runState, prs []*github.PullRequest, newPR *github.PullRequest) {
+	for _, pr := range prs {
+		if pr.GetNumber() == newPR.GetNumber() {
+			continue
+		}
+		logger := logger.With("pr_number", pr.GetNumber())
+		logger.Info("closing a superseded pull request")
+		if err := c.closePR(ctx, state, pr.GetNumber(), fmt.Sprintf("This pull request was superseded by #%d.", newPR.GetNumber())); err != nil {
+			slogerr.WithError(logger, err).Error("close a superseded pull request")
+		}
+	}
+}
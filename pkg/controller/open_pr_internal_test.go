@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+)
+
+func Test_parseUpdateBranch(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	pkgs := map[string]struct{}{
+		"suzuki-shunsuke/tfcmt":               {},
+		"suzuki-shunsuke/tfcmt-plugin":        {},
+		"kubernetes-sigs/kustomize":           {},
+		"kubernetes/kubernetes/kubectl":       {},
+		"kubernetes/kubernetes/kubectl-proxy": {},
+	}
+	exists := func(pkgName string) bool {
+		_, ok := pkgs[pkgName]
+		return ok
+	}
+	data := []struct {
+		name    string
+		branch  string
+		pkgName string
+		version string
+		isErr   bool
+	}{
+		{
+			name:    "normal",
+			branch:  "aqua-registry-updater-suzuki-shunsuke/tfcmt-v4.0.0",
+			pkgName: "suzuki-shunsuke/tfcmt",
+			version: "v4.0.0",
+		},
+		{
+			name:    "package name is a prefix of another package",
+			branch:  "aqua-registry-updater-suzuki-shunsuke/tfcmt-plugin-v1.0.0",
+			pkgName: "suzuki-shunsuke/tfcmt-plugin",
+			version: "v1.0.0",
+		},
+		{
+			name:    "version includes a slash",
+			branch:  "aqua-registry-updater-kubernetes-sigs/kustomize-kustomize/v5.3.0",
+			pkgName: "kubernetes-sigs/kustomize",
+			version: "kustomize/v5.3.0",
+		},
+		{
+			name:    "version includes a hyphen",
+			branch:  "aqua-registry-updater-kubernetes/kubernetes/kubectl-v1.30.0-rc.1",
+			pkgName: "kubernetes/kubernetes/kubectl",
+			version: "v1.30.0-rc.1",
+		},
+		{
+			name:   "scaffold",
+			branch: "aqua-registry-updater-scaffold-suzuki-shunsuke/tfcmt",
+			isErr:  true,
+		},
+		{
+			name:   "unknown package",
+			branch: "aqua-registry-updater-foo/bar-v1.0.0",
+			isErr:  true,
+		},
+		{
+			name:   "other branch",
+			branch: "renovate/suzuki-shunsuke-tfcmt-4.x",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			pkgName, version, ok := parseUpdateBranch(d.branch, exists)
+			if !ok {
+				if d.isErr {
+					return
+				}
+				t.Fatal("the branch must be parsed")
+			}
+			if d.isErr {
+				t.Fatal("the branch must not be parsed")
+			}
+			if pkgName != d.pkgName {
+				t.Fatalf("package name: wanted %v, got %v", d.pkgName, pkgName)
+			}
+			if version != d.version {
+				t.Fatalf("version: wanted %v, got %v", d.version, version)
+			}
+		})
+	}
+}
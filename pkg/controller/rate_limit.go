@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// RateLimitConfig is the configuration to stop processing before GitHub API rate limits are exhausted.
+type RateLimitConfig struct {
+	// CoreReserve is the number of requests of the core rate limit kept for other workflows.
+	CoreReserve *int `yaml:"core_reserve"`
+	// GraphQLReserve is the number of points of the GraphQL rate limit kept for other workflows.
+	GraphQLReserve *int `yaml:"graphql_reserve"`
+	// Check calls the rate limit API at the start of a run to get the remaining rate limits.
+	Check bool
+	// MaxRetryAfter is the maximum duration to wait for when a secondary rate limit is hit.
+	// If GitHub requires waiting longer, the request fails.
+	MaxRetryAfter time.Duration `yaml:"max_retry_after"`
+}
+
+const (
+	defaultCoreReserve    = 200
+	defaultGraphQLReserve = 100
+	defaultMaxRetryAfter  = 3 * time.Minute
+	// maxSecondaryRateLimitRetries is the maximum number of retries when a secondary rate limit is hit.
+	maxSecondaryRateLimitRetries = 3
+)
+
+// RateLimit tracks remaining GitHub API rate limits.
+// They are read from response headers, so all requests must be sent via Transport.
+type RateLimit struct {
+	mu            sync.Mutex
+	remaining     map[string]int
+	maxRetryAfter time.Duration
+}
+
+func NewRateLimit() *RateLimit {
+	return &RateLimit{
+		remaining:     map[string]int{},
+		maxRetryAfter: defaultMaxRetryAfter,
+	}
+}
+
+func (r *RateLimit) setMaxRetryAfter(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxRetryAfter = d
+}
+
+func (r *RateLimit) getMaxRetryAfter() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.maxRetryAfter
+}
+
+func (r *RateLimit) set(resource string, remaining int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remaining[resource] = remaining
+}
+
+// Remaining returns the remaining rate limit of the resource such as "core" and "graphql".
+// If the rate limit isn't known yet, it returns false.
+func (r *RateLimit) Remaining(resource string) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining, ok := r.remaining[resource]
+	return remaining, ok
+}
+
+// Transport returns a http.RoundTripper which records rate limits and waits for secondary rate limits.
+func (r *RateLimit) Transport(base http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{
+		base:      base,
+		rateLimit: r,
+	}
+}
+
+type rateLimitTransport struct {
+	base      http.RoundTripper
+	rateLimit *RateLimit
+}
+
+// RoundTrip sends the request and retries it when a secondary rate limit is hit.
+// The caller's request isn't modified, so each retry sends a clone with a new body.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := req
+	for i := 0; ; i++ {
+		resp, err := t.base.RoundTrip(attempt)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		t.record(resp)
+		retryAfter, ok := t.retryAfter(resp)
+		if !ok || i == maxSecondaryRateLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err() //nolint:wrapcheck
+		case <-timer.C:
+		}
+		attempt = req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("get a request body to retry: %w", err)
+			}
+			attempt.Body = body
+		}
+	}
+}
+
+func (t *rateLimitTransport) record(resp *http.Response) {
+	resource := resp.Header.Get("X-Ratelimit-Resource")
+	if resource == "" {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return
+	}
+	t.rateLimit.set(resource, remaining)
+}
+
+// retryAfter returns the duration to wait for if a secondary rate limit is hit.
+// GitHub returns the header Retry-After with the status code 403 or 429 when a secondary rate limit is hit.
+func (t *rateLimitTransport) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	sec, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return 0, false
+	}
+	d := time.Duration(sec) * time.Second
+	if d > t.rateLimit.getMaxRetryAfter() {
+		return 0, false
+	}
+	return d, true
+}
+
+// checkRateLimit gets the remaining rate limits through the rate limit API.
+func (c *Controller) checkRateLimit(ctx context.Context) error {
+	limits, _, err := c.rates.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("get rate limits: %w", err)
+	}
+	if core := limits.GetCore(); core != nil {
+		c.param.RateLimit.set("core", core.Remaining)
+	}
+	if gql := limits.GetGraphQL(); gql != nil {
+		c.param.RateLimit.set("graphql", gql.Remaining)
+	}
+	return nil
+}
+
+// isRateLimitExceeded returns true if the remaining rate limit is below the reserve.
+func (c *Controller) isRateLimitExceeded(logger *slog.Logger, cfg *RateLimitConfig) bool {
+	for resource, reserve := range map[string]int{
+		"core":    *cfg.CoreReserve,
+		"graphql": *cfg.GraphQLReserve,
+	} {
+		remaining, ok := c.param.RateLimit.Remaining(resource)
+		if ok && remaining < reserve {
+			logger.Warn("the remaining rate limit is below the reserve", "resource", resource, "remaining", remaining, "reserve", reserve)
+			return true
+		}
+	}
+	return false
+}
+
+// isRateLimitError returns true if err is caused by GitHub API rate limiting.
+func isRateLimitError(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_rateLimitTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+	reqs := []*http.Request{}
+	bodies := []string{}
+	transport := NewRateLimit().Transport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		reqs = append(reqs, req)
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		bodies = append(bodies, string(b))
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+		if len(reqs) == 1 {
+			resp.StatusCode = http.StatusTooManyRequests
+			resp.Header.Set("Retry-After", "0")
+		}
+		return resp, nil
+	}))
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://api.github.com/repos/aquaproj/aqua-registry/git/trees", strings.NewReader(`{"tree":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wanted %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("the request must be retried once, got %d requests", len(reqs))
+	}
+	if reqs[1] == req {
+		t.Fatal("the retry must send a clone of the request")
+	}
+	if req.Body != body {
+		t.Fatal("the caller's request must not be modified")
+	}
+	for i, b := range bodies {
+		if b != `{"tree":[]}` {
+			t.Fatalf("the body of the request %d is wrong: %s", i, b)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+// reconcile closes update pull requests which are obsolete because the default branch already has the version or a newer version.
+func (c *Controller) reconcile(ctx context.Context, logger *slog.Logger, state *runState) {
+	for _, pr := range state.openPRs {
+		branch := pr.GetHead().GetRef()
+		pkgName, version, ok := parseUpdateBranch(branch, c.pkgExists)
+		if !ok {
+			continue
+		}
+		logger := logger.With("pkg_name", pkgName, "pr_number", pr.GetNumber())
+		body, err := afero.ReadFile(c.fs, filepath.Join("pkgs", filepath.FromSlash(pkgName), "pkg.yaml"))
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("read pkg.yaml")
+			continue
+		}
+		currentVersion, err := c.getCurrentVersion(pkgName, string(body))
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("get the current version")
+			continue
+		}
+		obsolete, err := isObsolete(currentVersion, version)
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("compare version")
+			continue
+		}
+		if !obsolete {
+			continue
+		}
+		logger.Info("closing an obsolete pull request", "current_version", currentVersion, "version", version)
+		if err := c.closePR(ctx, state, pr.GetNumber(), fmt.Sprintf("This pull request was closed because %s is already %s.", pkgName, currentVersion)); err != nil {
+			slogerr.WithError(logger, err).Error("close an obsolete pull request")
+			continue
+		}
+		if err := c.deleteBranch(ctx, branch); err != nil {
+			slogerr.WithError(logger, err).Error("delete a branch")
+		}
+	}
+}
+
+// isObsolete returns true if the current version is equal to or newer than the version of a pull request.
+// If their prefixes are different, the pull request isn't obsolete.
+func isObsolete(currentVersion, prVersion string) (bool, error) {
+	if currentVersion == prVersion {
+		return true, nil
+	}
+	return compareVersion(prVersion, currentVersion)
+}
+
+func (c *Controller) deleteBranch(ctx context.Context, branch string) error {
+	if _, err := c.git.DeleteRef(ctx, c.param.RepoOwner, c.param.RepoName, "heads/"+branch); err != nil {
+		return fmt.Errorf("delete a branch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/spf13/afero"
+)
+
+func Test_isObsolete(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name           string
+		currentVersion string
+		prVersion      string
+		f              bool
+		isErr          bool
+	}{
+		{
+			name:           "equal",
+			currentVersion: "v2.0.0",
+			prVersion:      "v2.0.0",
+			f:              true,
+		},
+		{
+			name:           "current version is newer",
+			currentVersion: "v2.1.0",
+			prVersion:      "v2.0.0",
+			f:              true,
+		},
+		{
+			name:           "pull request version is newer",
+			currentVersion: "v2.0.0",
+			prVersion:      "v2.1.0",
+		},
+		{
+			name:           "different prefix",
+			currentVersion: "cli-v2.1.0",
+			prVersion:      "v2.0.0",
+		},
+		{
+			name:           "same prefix",
+			currentVersion: "cli-v2.1.0",
+			prVersion:      "cli-v2.0.0",
+			f:              true,
+		},
+		{
+			name:           "equal commit hash",
+			currentVersion: "cd684900348e6c23335064bf74c8368e3abcec5e",
+			prVersion:      "cd684900348e6c23335064bf74c8368e3abcec5e",
+			f:              true,
+		},
+		{
+			name:           "current version is a commit hash",
+			currentVersion: "cd684900348e6c23335064bf74c8368e3abcec5e",
+			prVersion:      "v0.1.3",
+			isErr:          true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			f, err := isObsolete(d.currentVersion, d.prVersion)
+			if err != nil {
+				if d.isErr {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if f != d.f {
+				t.Fatalf("wanted %v, got %v", d.f, f)
+			}
+		})
+	}
+}
+
+// fakePulls records pull requests closed through PullRequestsService.
+type fakePulls struct {
+	PullRequestsService
+
+	closed []int
+}
+
+func (f *fakePulls) Edit(_ context.Context, _, _ string, number int, pull *github.PullRequest) (*github.PullRequest, *github.Response, error) {
+	if pull.GetState() == "closed" {
+		f.closed = append(f.closed, number)
+	}
+	return pull, &github.Response{}, nil
+}
+
+func (f *fakeIssues) CreateComment(_ context.Context, _, _ string, _ int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	return comment, &github.Response{}, nil
+}
+
+func TestController_reconcile(t *testing.T) {
+	t.Parallel()
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "pkgs/foo/bar/pkg.yaml", []byte("packages:\n  - name: foo/bar@v1.1.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git := newFakeGit()
+	pulls := &fakePulls{}
+	openPRs := []*github.PullRequest{}
+	for i, version := range []string{"v1.0.0", "v1.1.0", "v1.2.0", "cli-v1.0.0", "cd684900348e6c23335064bf74c8368e3abcec5e"} {
+		branch := "aqua-registry-updater-foo/bar-" + version
+		git.refs["heads/"+branch] = "root"
+		openPRs = append(openPRs, &github.PullRequest{
+			Number: new(i + 1),
+			Head:   &github.PullRequestBranch{Ref: new(branch)},
+		})
+	}
+	ctrl := &Controller{
+		fs:    fs,
+		git:   git,
+		pull:  pulls,
+		issue: &fakeIssues{},
+		param: &ParamNew{RepoOwner: "aquaproj", RepoName: "aqua-registry"},
+	}
+	state := &runState{openPRs: openPRs}
+	ctrl.reconcile(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), state)
+	if want := []int{1, 2}; !slices.Equal(pulls.closed, want) {
+		t.Fatalf("wanted closed pull requests %v, got %v", want, pulls.closed)
+	}
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		if _, ok := git.refs["heads/aqua-registry-updater-foo/bar-"+version]; ok {
+			t.Fatalf("the branch of %s must be deleted", version)
+		}
+	}
+	for _, version := range []string{"v1.2.0", "cli-v1.0.0", "cd684900348e6c23335064bf74c8368e3abcec5e"} {
+		if _, ok := git.refs["heads/aqua-registry-updater-foo/bar-"+version]; !ok {
+			t.Fatalf("the branch of %s must not be deleted", version)
+		}
+	}
+	if len(state.openPRs) != 3 {
+		t.Fatalf("closed pull requests must be removed from open pull requests, got %d", len(state.openPRs))
+	}
+}
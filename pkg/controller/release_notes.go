@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v89/github"
+)
+
+const (
+	// maxPRBodyLength is the maximum length of pull request bodies GitHub accepts.
+	maxPRBodyLength = 65536
+	// maxReleaseNotesLength is the maximum length of release notes embedded into pull request bodies.
+	// Room is left for the rest of the body.
+	maxReleaseNotesLength = 50000
+	// maxReleasePages is the maximum number of pages of releases to search releases skipped since the current version.
+	maxReleasePages = 3
+	// maxReleaseNotes is the maximum number of release notes embedded into pull request bodies.
+	maxReleaseNotes = 20
+)
+
+// Release is a GitHub Release between the current version and the new version.
+type Release struct {
+	TagName string
+	URL     string
+	Body    string
+}
+
+// listReleases returns releases newer than currentVersion and older than or equal to newVersion.
+// Releases are sorted from newest to oldest.
+func (c *Controller) listReleases(ctx context.Context, repoOwner, repoName, currentVersion, newVersion string) ([]*Release, error) {
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	releases := []*Release{}
+	for range maxReleasePages {
+		arr, resp, err := c.repo.ListReleases(ctx, repoOwner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list releases: %w", err)
+		}
+		foundCurrent := false
+		for _, release := range arr {
+			tag := release.GetTagName()
+			if tag == currentVersion {
+				foundCurrent = true
+			}
+			if !isReleaseInRange(release, currentVersion, newVersion) {
+				continue
+			}
+			releases = append(releases, &Release{
+				TagName: tag,
+				URL:     release.GetHTMLURL(),
+				Body:    release.GetBody(),
+			})
+		}
+		if foundCurrent || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return releases, nil
+}
+
+func isReleaseInRange(release *github.RepositoryRelease, currentVersion, newVersion string) bool {
+	if release.GetDraft() {
+		return false
+	}
+	tag := release.GetTagName()
+	if tag == newVersion {
+		return true
+	}
+	if release.GetPrerelease() {
+		return false
+	}
+	if newer, err := compareVersion(currentVersion, tag); err != nil || !newer {
+		return false
+	}
+	older, err := compareVersion(tag, newVersion)
+	return err == nil && older
+}
+
+// formatReleaseNotes formats release notes as collapsible blocks.
+// Each release note is truncated so that the total length doesn't exceed maxLength.
+// Only the newest maxReleaseNotes releases are formatted.
+func formatReleaseNotes(releases []*Release, maxLength int) string {
+	if len(releases) == 0 {
+		return ""
+	}
+	omitted := 0
+	if len(releases) > maxReleaseNotes {
+		omitted = len(releases) - maxReleaseNotes
+		releases = releases[:maxReleaseNotes]
+	}
+	limit := maxLength / len(releases)
+	b := &strings.Builder{}
+	for _, release := range releases {
+		fmt.Fprintf(b, "<details>\n<summary><a href=\"%s\">%s</a></summary>\n\n%s\n\n</details>\n\n", release.URL, release.TagName, truncate(strings.TrimSpace(release.Body), limit))
+	}
+	if omitted != 0 {
+		fmt.Fprintf(b, "Release notes of %d older releases are omitted.\n\n", omitted)
+	}
+	return b.String()
+}
+
+const truncatedMessage = "\n\n... (truncated)"
+
+// truncate truncates s so that the length of the result doesn't exceed limit bytes.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	limit -= len(truncatedMessage)
+	if limit <= 0 {
+		return ""
+	}
+	// Avoid breaking a multibyte character
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit] + truncatedMessage
+}
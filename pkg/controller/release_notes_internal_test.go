@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func Test_truncate(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name  string
+		s     string
+		limit int
+		exp   string
+	}{
+		{
+			name:  "short",
+			s:     "hello",
+			limit: 10,
+			exp:   "hello",
+		},
+		{
+			name:  "truncated",
+			s:     strings.Repeat("a", 30),
+			limit: 20,
+			exp:   "aaa" + truncatedMessage,
+		},
+		{
+			name:  "multibyte",
+			s:     strings.Repeat("あ", 10),
+			limit: 21,
+			exp:   "あ" + truncatedMessage,
+		},
+		{
+			name:  "limit is too small",
+			s:     strings.Repeat("a", 30),
+			limit: 5,
+			exp:   "",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			s := truncate(d.s, d.limit)
+			if s != d.exp {
+				t.Fatalf("wanted %q, got %q", d.exp, s)
+			}
+			if len(s) > d.limit {
+				t.Fatalf("the length %d exceeds the limit %d", len(s), d.limit)
+			}
+			if !utf8.ValidString(s) {
+				t.Fatalf("the result isn't valid UTF-8: %q", s)
+			}
+		})
+	}
+}
+
+func Test_isReleaseInRange(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name    string
+		release *github.RepositoryRelease
+		exp     bool
+	}{
+		{
+			name:    "new version",
+			release: &github.RepositoryRelease{TagName: "v1.3.0"},
+			exp:     true,
+		},
+		{
+			name:    "new version is a prerelease",
+			release: &github.RepositoryRelease{TagName: "v1.3.0", Prerelease: true},
+			exp:     true,
+		},
+		{
+			name:    "between",
+			release: &github.RepositoryRelease{TagName: "v1.2.0"},
+			exp:     true,
+		},
+		{
+			name:    "current version",
+			release: &github.RepositoryRelease{TagName: "v1.1.0"},
+		},
+		{
+			name:    "older",
+			release: &github.RepositoryRelease{TagName: "v1.0.0"},
+		},
+		{
+			name:    "newer",
+			release: &github.RepositoryRelease{TagName: "v1.4.0"},
+		},
+		{
+			name:    "draft",
+			release: &github.RepositoryRelease{TagName: "v1.2.0", Draft: true},
+		},
+		{
+			name:    "prerelease",
+			release: &github.RepositoryRelease{TagName: "v1.2.0", Prerelease: true},
+		},
+		{
+			name:    "different prefix",
+			release: &github.RepositoryRelease{TagName: "cli-v1.2.0"},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			if f := isReleaseInRange(d.release, "v1.1.0", "v1.3.0"); f != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, f)
+			}
+		})
+	}
+}
+
+func Test_formatReleaseNotes(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name     string
+		releases int
+		omitted  string
+	}{
+		{
+			name: "no release",
+		},
+		{
+			name:     "not omitted",
+			releases: maxReleaseNotes,
+		},
+		{
+			name:     "omitted",
+			releases: maxReleaseNotes + 3,
+			omitted:  "Release notes of 3 older releases are omitted.",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			releases := make([]*Release, d.releases)
+			for i := range releases {
+				releases[i] = &Release{
+					TagName: fmt.Sprintf("v1.%d.0", d.releases-i),
+					URL:     "https://github.com/foo/bar/releases",
+					Body:    strings.Repeat("a", 1000),
+				}
+			}
+			s := formatReleaseNotes(releases, 10000)
+			if d.releases == 0 {
+				if s != "" {
+					t.Fatalf("wanted an empty string, got %q", s)
+				}
+				return
+			}
+			if n := strings.Count(s, "<details>"); n != min(d.releases, maxReleaseNotes) {
+				t.Fatalf("wanted %d release notes, got %d", min(d.releases, maxReleaseNotes), n)
+			}
+			if len(s) > 10000+maxReleaseNotes*200 {
+				t.Fatalf("release notes must be truncated, got %d bytes", len(s))
+			}
+			if d.omitted == "" {
+				if strings.Contains(s, "omitted") {
+					t.Fatalf("release notes must not be omitted: %s", s)
+				}
+				return
+			}
+			if !strings.Contains(s, d.omitted) {
+				t.Fatalf("wanted %q in %s", d.omitted, s)
+			}
+		})
+	}
+}
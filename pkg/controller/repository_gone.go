@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"github.com/google/go-github/v89/github"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+// RepositoryGoneConfig is the configuration to create issues tracking packages whose GitHub repositories were deleted or became inaccessible.
+// It requires the permission issues:write.
+type RepositoryGoneConfig struct {
+	Enabled bool
+}
+
+func (r *RepositoryGoneConfig) IsEnabled() bool {
+	return r != nil && r.Enabled
+}
+
+// upstreamRepository is the GitHub repository of a package.
+type upstreamRepository struct {
+	pkgInfo *registry.PackageInfo
+	// repo is nil if the repository isn't found.
+	repo *github.Repository
+}
+
+// getUpstreamRepository gets the package's GitHub repository.
+// If the package isn't hosted on GitHub, it returns nil.
+func (c *Controller) getUpstreamRepository(ctx context.Context, pkgName string) (*upstreamRepository, error) {
+	pkgInfo, err := c.readPackageInfo(pkgName)
+	if err != nil {
+		return nil, err
+	}
+	if pkgInfo.RepoOwner == "" || pkgInfo.RepoName == "" || strings.Contains(pkgInfo.RepoOwner, ".") {
+		return nil, nil //nolint:nilnil
+	}
+	repo, resp, err := c.repo.Get(ctx, pkgInfo.RepoOwner, pkgInfo.RepoName)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return &upstreamRepository{pkgInfo: pkgInfo}, nil
+		}
+		return nil, fmt.Errorf("get a repository: %w", err)
+	}
+	return &upstreamRepository{
+		pkgInfo: pkgInfo,
+		repo:    repo,
+	}, nil
+}
+
+// checkUpstream handles the package if its repository is gone or archived.
+// It returns true if the package is handled and the version update should be skipped.
+// Failures to get the repository such as 403 are only logged, because only a confirmed 404 means the repository is gone.
+// Rate limit errors are returned so that Update stops handling packages.
+func (c *Controller) checkUpstream(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, state *runState) (bool, error) {
+	if !cfg.RepositoryGone.IsEnabled() && !cfg.Archived.IsEnabled() {
+		return false, nil
+	}
+	upstream, err := c.getUpstreamRepository(ctx, pkg.Name)
+	if err != nil {
+		if isRateLimitError(err) {
+			return false, err
+		}
+		slogerr.WithError(logger, err).Warn("get the package's repository")
+		return false, nil
+	}
+	if upstream == nil {
+		return false, nil
+	}
+	if cfg.RepositoryGone.IsEnabled() {
+		gone, err := c.handleRepositoryGone(ctx, logger, pkg, cfg, upstream)
+		if err != nil {
+			return false, err
+		}
+		if gone {
+			return true, nil
+		}
+	}
+	if cfg.Archived.IsEnabled() {
+		return c.handleArchived(ctx, logger, pkg, cfg, state, upstream)
+	}
+	return false, nil
+}
+
+// handleRepositoryGone creates a tracking issue if the package's repository was deleted or became inaccessible.
+// The issue number is stored in data.json, so the package is skipped without creating issues again until the repository becomes accessible or the package is removed.
+// It returns true if the repository is gone.
+func (c *Controller) handleRepositoryGone(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, upstream *upstreamRepository) (bool, error) {
+	if upstream.repo != nil {
+		if pkg.RepositoryGone != nil {
+			logger.Info("the package's repository is accessible again", "issue_number", pkg.RepositoryGone.IssueNumber)
+			pkg.RepositoryGone = nil
+		}
+		return false, nil
+	}
+	if pkg.RepositoryGone != nil {
+		logger.Info("skip the package because the repository is gone", "issue_number", pkg.RepositoryGone.IssueNumber)
+		return true, nil
+	}
+	logger.Warn("the package's repository is gone", "repo_owner", upstream.pkgInfo.RepoOwner, "repo_name", upstream.pkgInfo.RepoName)
+	paramTemplates := &ParamTemplates{
+		ServerURL:   c.param.URLs.ServerURL,
+		PackageName: pkg.Name,
+		RepoOwner:   upstream.pkgInfo.RepoOwner,
+		RepoName:    upstream.pkgInfo.RepoName,
+	}
+	title, err := renderTemplate(cfg.compiledTemplates.RepositoryGoneIssueTitle, paramTemplates)
+	if err != nil {
+		return false, fmt.Errorf("render a template repository_gone_issue_title: %w", err)
+	}
+	body, err := renderTemplate(cfg.compiledTemplates.RepositoryGoneIssueBody, paramTemplates)
+	if err != nil {
+		return false, fmt.Errorf("render a template repository_gone_issue_body: %w", err)
+	}
+	issue, _, err := c.issue.Create(ctx, c.param.RepoOwner, c.param.RepoName, &github.IssueRequest{
+		Title: new(title),
+		Body:  new(body),
+	})
+	if err != nil {
+		return false, fmt.Errorf("create an issue: %w", err)
+	}
+	logger.Info("created an issue", "issue_number", issue.GetNumber())
+	pkg.RepositoryGone = &RepositoryGone{
+		IssueNumber: issue.GetNumber(),
+	}
+	return true, nil
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"github.com/google/go-github/v89/github"
+	"github.com/spf13/afero"
+)
+
+// fakeIssues records issues created through IssuesService.
+type fakeIssues struct {
+	IssuesService
+
+	created []*github.IssueRequest
+}
+
+func (f *fakeIssues) Create(_ context.Context, _, _ string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	f.created = append(f.created, issue)
+	return &github.Issue{Number: new(100 + len(f.created))}, &github.Response{}, nil
+}
+
+func TestController_handleRepositoryGone(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name    string
+		pkg     *Package
+		repo    *github.Repository
+		exp     bool
+		created int
+		gone    *RepositoryGone
+	}{
+		{
+			name: "accessible",
+			pkg:  &Package{Name: "foo/bar"},
+			repo: &github.Repository{},
+		},
+		{
+			name: "accessible again",
+			pkg:  &Package{Name: "foo/bar", RepositoryGone: &RepositoryGone{IssueNumber: 10}},
+			repo: &github.Repository{},
+		},
+		{
+			name:    "gone",
+			pkg:     &Package{Name: "foo/bar"},
+			exp:     true,
+			created: 1,
+			gone:    &RepositoryGone{IssueNumber: 101},
+		},
+		{
+			name: "already tracked",
+			pkg:  &Package{Name: "foo/bar", RepositoryGone: &RepositoryGone{IssueNumber: 10}},
+			exp:  true,
+			gone: &RepositoryGone{IssueNumber: 10},
+		},
+	}
+	title, err := compileTemplate("{{.PackageName}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		compiledTemplates: &CompiledTemplates{
+			RepositoryGoneIssueTitle: title,
+			RepositoryGoneIssueBody:  title,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			issues := &fakeIssues{}
+			ctrl := &Controller{
+				issue: issues,
+				param: &ParamNew{RepoOwner: "aquaproj", RepoName: "aqua-registry", URLs: &GitHubURLs{ServerURL: defaultServerURL}},
+			}
+			gone, err := ctrl.handleRepositoryGone(context.Background(), logger, d.pkg, cfg, &upstreamRepository{
+				pkgInfo: &registry.PackageInfo{RepoOwner: "foo", RepoName: "bar"},
+				repo:    d.repo,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gone != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, gone)
+			}
+			if len(issues.created) != d.created {
+				t.Fatalf("wanted %d issues, got %d", d.created, len(issues.created))
+			}
+			if (d.gone == nil) != (d.pkg.RepositoryGone == nil) || (d.gone != nil && d.gone.IssueNumber != d.pkg.RepositoryGone.IssueNumber) {
+				t.Fatalf("wanted %+v, got %+v", d.gone, d.pkg.RepositoryGone)
+			}
+		})
+	}
+}
+
+// fakeRepositories returns the response of the status code from Get.
+type fakeRepositories struct {
+	RepositoriesService
+
+	statusCode int
+}
+
+func (f *fakeRepositories) Get(_ context.Context, _, _ string) (*github.Repository, *github.Response, error) {
+	if f.statusCode == http.StatusTooManyRequests {
+		resp := &github.Response{Response: &http.Response{StatusCode: f.statusCode}}
+		return nil, resp, &github.RateLimitError{Response: resp.Response}
+	}
+	if f.statusCode != http.StatusOK {
+		return nil, &github.Response{Response: &http.Response{StatusCode: f.statusCode}}, errors.New(http.StatusText(f.statusCode))
+	}
+	return &github.Repository{}, &github.Response{Response: &http.Response{StatusCode: f.statusCode}}, nil
+}
+
+func TestController_checkUpstream(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name       string
+		registry   string
+		statusCode int
+		disabled   bool
+		exp        bool
+		isErr      bool
+		created    int
+	}{
+		{
+			name:       "accessible",
+			registry:   "packages:\n  - type: github_release\n    repo_owner: foo\n    repo_name: bar\n",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "gone",
+			registry:   "packages:\n  - type: github_release\n    repo_owner: foo\n    repo_name: bar\n",
+			statusCode: http.StatusNotFound,
+			exp:        true,
+			created:    1,
+		},
+		{
+			name:       "disabled",
+			registry:   "packages:\n  - type: github_release\n    repo_owner: foo\n    repo_name: bar\n",
+			statusCode: http.StatusNotFound,
+			disabled:   true,
+		},
+		{
+			name:       "forbidden",
+			registry:   "packages:\n  - type: github_release\n    repo_owner: foo\n    repo_name: bar\n",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "rate limit",
+			registry:   "packages:\n  - type: github_release\n    repo_owner: foo\n    repo_name: bar\n",
+			statusCode: http.StatusTooManyRequests,
+			isErr:      true,
+		},
+		{
+			name:       "registry.yaml isn't found",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "multiple packages",
+			registry:   "packages:\n  - repo_owner: foo\n    repo_name: bar\n  - repo_owner: foo\n    repo_name: baz\n",
+			statusCode: http.StatusNotFound,
+		},
+	}
+	title, err := compileTemplate("{{.PackageName}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Config{
+				RepositoryGone: &RepositoryGoneConfig{Enabled: !d.disabled},
+				compiledTemplates: &CompiledTemplates{
+					RepositoryGoneIssueTitle: title,
+					RepositoryGoneIssueBody:  title,
+				},
+			}
+			fs := afero.NewMemMapFs()
+			if d.registry != "" {
+				if err := afero.WriteFile(fs, "pkgs/foo/bar/registry.yaml", []byte(d.registry), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			issues := &fakeIssues{}
+			ctrl := &Controller{
+				fs:    fs,
+				repo:  &fakeRepositories{statusCode: d.statusCode},
+				issue: issues,
+				param: &ParamNew{RepoOwner: "aquaproj", RepoName: "aqua-registry", URLs: &GitHubURLs{ServerURL: defaultServerURL}},
+			}
+			handled, err := ctrl.checkUpstream(context.Background(), logger, &Package{Name: "foo/bar"}, cfg, &runState{})
+			if err != nil {
+				if d.isErr && isRateLimitError(err) {
+					return
+				}
+				t.Fatal(err)
+			}
+			if d.isErr {
+				t.Fatal("error must be returned")
+			}
+			if handled != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, handled)
+			}
+			if len(issues.created) != d.created {
+				t.Fatalf("wanted %d issues, got %d", d.created, len(issues.created))
+			}
+		})
+	}
+}
@@ -5,65 +5,77 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/aquaproj/aqua/v2/pkg/config/registry"
 	genrg "github.com/aquaproj/registry-tool/pkg/generate-registry"
+	"github.com/google/go-github/v89/github"
 	"github.com/spf13/afero"
 	"github.com/suzuki-shunsuke/go-exec/goexec"
 	"gopkg.in/yaml.v3"
 )
 
-func (c *Controller) checkBranch(ctx context.Context, branch string) (f bool, e error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://github.com/aquaproj/aqua-registry/tree/"+branch, nil)
+// checkBranch returns true if the branch exists in the repository.
+func (c *Controller) checkBranch(ctx context.Context, branch string) (bool, error) {
+	_, exists, err := c.getRefSHA(ctx, "heads/"+branch)
 	if err != nil {
-		return false, fmt.Errorf("create a http request: %w", err)
+		return false, err
 	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return false, fmt.Errorf("send a http request: %w", err)
-	}
-	defer resp.Body.Close()
-	return resp.StatusCode == http.StatusOK, nil
+	return exists, nil
 }
 
-func (c *Controller) scaffold(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config) (f bool, e error) { //nolint:cyclop,funlen
-	branch := "aqua-registry-updater-scaffold-" + pkg.Name
-	if ok, err := c.checkBranch(ctx, branch); err != nil {
-		return false, fmt.Errorf("check a branch: %w", err)
-	} else if ok {
-		return true, nil
-	}
-
-	pkgPath := filepath.Join("pkgs", pkg.Name, "pkg.yaml")
-	registryPath := filepath.Join("pkgs", pkg.Name, "registry.yaml")
-	body, err := afero.ReadFile(c.fs, registryPath)
+// readPackageInfo reads the package's registry.yaml, which must have only one package.
+func (c *Controller) readPackageInfo(pkgName string) (*registry.PackageInfo, error) {
+	body, err := afero.ReadFile(c.fs, filepath.Join("pkgs", filepath.FromSlash(pkgName), "registry.yaml"))
 	if err != nil {
-		return false, fmt.Errorf("read registry.yaml: %w", err)
+		return nil, fmt.Errorf("read registry.yaml: %w", err)
 	}
 	rCfg := &registry.Config{}
 	if err := yaml.Unmarshal(body, rCfg); err != nil {
-		return false, fmt.Errorf("unmarshal registry.yaml as YAML: %w", err)
+		return nil, fmt.Errorf("unmarshal registry.yaml as YAML: %w", err)
 	}
 	if len(rCfg.PackageInfos) == 0 {
-		return false, errors.New("registry.yaml is empty")
+		return nil, errors.New("registry.yaml is empty")
 	}
 	if len(rCfg.PackageInfos) != 1 {
-		return false, errors.New("registry.yaml must have only one package")
+		return nil, errors.New("registry.yaml must have only one package")
 	}
 	pkgInfo := rCfg.PackageInfos[0]
 	if pkgInfo == nil {
-		return false, errors.New("package is nil")
+		return nil, errors.New("package is nil")
+	}
+	return pkgInfo, nil
+}
+
+func (c *Controller) scaffold(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, state *runState) (f bool, e error) { //nolint:cyclop,funlen
+	if !pkg.isScaffoldDue(time.Now(), cfg.Scaffold.Interval) {
+		return false, nil
 	}
-	if pkgInfo.Type != "github_release" {
+	branch := branchPrefix + "scaffold-" + pkg.Name
+	if ok, err := c.checkBranch(ctx, branch); err != nil {
+		return false, fmt.Errorf("check a branch: %w", err)
+	} else if ok {
+		return true, nil
+	}
+
+	pkgPath := filepath.Join("pkgs", pkg.Name, "pkg.yaml")
+	registryPath := filepath.Join("pkgs", pkg.Name, "registry.yaml")
+	pkgInfo, err := c.readPackageInfo(pkg.Name)
+	if err != nil {
+		return false, err
+	}
+	if !slices.Contains(cfg.Scaffold.Types, pkgInfo.Type) {
 		return false, nil
 	}
 	if pkgInfo.VersionConstraints == "false" {
 		return false, nil
 	}
 	logger.Info("re-scaffolding")
+	// The time is recorded even if re-scaffolding fails so that the package isn't re-scaffolded on every run.
+	pkg.ScaffoldedAt = time.Now()
 	if err := c.fs.Remove(pkgPath); err != nil {
 		return false, fmt.Errorf("remove pkg.yaml: %w", err)
 	}
@@ -71,6 +83,10 @@ func (c *Controller) scaffold(ctx context.Context, logger *slog.Logger, pkg *Pac
 	if err != nil {
 		return false, fmt.Errorf("stat registry.yaml: %w", err)
 	}
+	oldBody, err := afero.ReadFile(c.fs, registryPath)
+	if err != nil {
+		return false, fmt.Errorf("read registry.yaml: %w", err)
+	}
 	registryFile, err := c.fs.OpenFile(registryPath, os.O_RDWR|os.O_TRUNC, stat.Mode())
 	if err != nil {
 		return false, fmt.Errorf("open registry.yaml: %w", err)
@@ -79,52 +95,131 @@ func (c *Controller) scaffold(ctx context.Context, logger *slog.Logger, pkg *Pac
 	if _, err := registryFile.WriteString("# yaml-language-server: $schema=https://raw.githubusercontent.com/aquaproj/aqua/main/json-schema/registry.json\n"); err != nil {
 		return false, fmt.Errorf("write yaml-language-server to registry.yaml: %w", err)
 	}
+	env, err := c.commandEnv()
+	if err != nil {
+		return false, err
+	}
 	cmd := goexec.Command(ctx, "aqua", "gr", "--out-testdata", pkgPath, pkg.Name)
 	cmd.Stdout = registryFile
+	cmd.Env = env
 	if err := cmd.Run(); err != nil {
 		return false, fmt.Errorf("run aqua gr %s: %w", pkg.Name, err)
 	}
+	if err := c.keepManualFields(logger, registryPath, oldBody, cfg.Scaffold.KeepFields); err != nil {
+		return false, err
+	}
+	newPkgInfo, err := c.readPackageInfo(pkg.Name)
+	if err != nil {
+		return false, fmt.Errorf("read the generated registry.yaml: %w", err)
+	}
+	changes, err := diffPackageInfo(pkgInfo, newPkgInfo)
+	if err != nil {
+		return false, fmt.Errorf("compare registry.yaml: %w", err)
+	}
+	if reason := scaffoldSkipReason(pkgInfo, newPkgInfo, changes); reason != "" {
+		logger.Info("a pull request isn't created", "reason", reason, "type", pkgInfo.Type, "new_type", newPkgInfo.Type)
+		if err := c.exec(ctx, "git", "checkout", "--", "."); err != nil {
+			return false, fmt.Errorf("restore files changed by aqua gr: %w", err)
+		}
+		return false, nil
+	}
 	if err := genrg.GenerateRegistry(ctx); err != nil {
 		return false, fmt.Errorf("update registry.yaml: %w", err)
 	}
-	if err := c.createScaffoldPR(ctx, pkg.Name, pkgInfo, cfg, branch); err != nil {
+	pr, err := c.createScaffoldPR(ctx, logger, pkg.Name, pkgInfo, cfg, branch, changes)
+	if errors.Is(err, errNothingToCommit) {
+		logger.Info("registry.yaml isn't changed from the base branch, so a pull request isn't created")
+		return false, nil
+	}
+	if err != nil {
 		return false, fmt.Errorf("create a pull request: %w", err)
 	}
+	state.openPRs = append(state.openPRs, pr)
 	return true, nil
 }
 
-func (c *Controller) createScaffoldPR(ctx context.Context, pkgName string, pkgInfo *registry.PackageInfo, cfg *Config, branch string) error {
+// scaffoldSkipReason returns the reason why a scaffold pull request isn't created.
+// Re-scaffolding must keep the package type.
+// aqua gr may generate a github_release package for a cargo package and vice versa.
+// If a pull request should be created, it returns an empty string.
+func scaffoldSkipReason(oldPkg, newPkg *registry.PackageInfo, changes []string) string {
+	if newPkg.Type != oldPkg.Type {
+		return "aqua gr changes the package type"
+	}
+	if len(changes) == 0 {
+		return "registry.yaml isn't changed meaningfully"
+	}
+	return ""
+}
+
+// keepManualFields overlays fields written by hand in the old registry.yaml on the generated registry.yaml.
+func (c *Controller) keepManualFields(logger *slog.Logger, registryPath string, oldBody []byte, keepFields []string) error {
+	stat, err := c.fs.Stat(registryPath)
+	if err != nil {
+		return fmt.Errorf("stat registry.yaml: %w", err)
+	}
+	newBody, err := afero.ReadFile(c.fs, registryPath)
+	if err != nil {
+		return fmt.Errorf("read the generated registry.yaml: %w", err)
+	}
+	b, kept, err := mergeManualFields(oldBody, newBody, keepFields)
+	if err != nil {
+		return fmt.Errorf("keep fields written by hand in registry.yaml: %w", err)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	logger.Info("keep fields written by hand in registry.yaml", "fields", kept)
+	if err := afero.WriteFile(c.fs, registryPath, b, stat.Mode()); err != nil {
+		return fmt.Errorf("write registry.yaml: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) createScaffoldPR(ctx context.Context, logger *slog.Logger, pkgName string, pkgInfo *registry.PackageInfo, cfg *Config, branch string, changes []string) (*github.PullRequest, error) {
+	prCfg := cfg.PullRequest.ForPackage(pkgName)
 	paramTemplates := &ParamTemplates{
-		PackageName: pkgName,
-		RepoOwner:   pkgInfo.RepoOwner,
-		RepoName:    pkgInfo.RepoName,
+		ServerURL:         c.param.URLs.ServerURL,
+		PackageName:       pkgName,
+		RepoOwner:         pkgInfo.RepoOwner,
+		RepoName:          pkgInfo.RepoName,
+		RegistryRepoOwner: c.param.RepoOwner,
+		RegistryRepoName:  c.param.RepoName,
+		BaseBranch:        prCfg.Base,
+		ScaffoldChanges:   formatScaffoldChanges(changes),
 	}
 
 	prTitle, err := renderTemplate(cfg.compiledTemplates.ScaffoldPRTitle, paramTemplates)
 	if err != nil {
-		return fmt.Errorf("render a template scaffold_pr_title: %w", err)
+		return nil, fmt.Errorf("render a template scaffold_pr_title: %w", err)
 	}
 
 	prBody, err := renderTemplate(cfg.compiledTemplates.ScaffoldPRBody, paramTemplates)
 	if err != nil {
-		return fmt.Errorf("render a template scaffold_pr_body: %w", err)
+		return nil, fmt.Errorf("render a template scaffold_pr_body: %w", err)
 	}
 
 	pkgDir := filepath.Join("pkgs", filepath.FromSlash(pkgName))
-	if err := c.exec(ctx, "ghcp", "commit",
-		"-r", fmt.Sprintf("%s/%s", c.param.RepoOwner, c.param.RepoName),
-		"-b", branch, "-m", prTitle,
-		"registry.yaml",
-		filepath.Join(pkgDir, "registry.yaml"),
-		filepath.Join(pkgDir, "pkg.yaml")); err != nil {
-		return fmt.Errorf("create a branch: %w", err)
-	}
-	if _, err := c.createPR(ctx, &ParamCreatePR{
+	if _, err := c.commit(ctx, &ParamCommit{
+		Base:    prCfg.Base,
+		Branch:  branch,
+		Message: prTitle,
+		Files: []string{
+			"registry.yaml",
+			filepath.Join(pkgDir, "registry.yaml"),
+			filepath.Join(pkgDir, "pkg.yaml"),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("create a branch: %w", err)
+	}
+	pr, err := c.createPR(ctx, logger, &ParamCreatePR{
 		Title:  prTitle,
 		Branch: branch,
 		Body:   prBody,
-	}); err != nil {
-		return fmt.Errorf("create a pull request: %w", err)
+		Config: prCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create a pull request: %w", err)
 	}
-	return nil
+	return pr, nil
 }
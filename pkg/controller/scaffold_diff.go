@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// scaffoldFields are fields of registry.yaml compared to decide if a scaffold pull request is created.
+// Metadata such as description are excluded because they don't affect the installation.
+var scaffoldFields = []struct { //nolint:gochecknoglobals
+	name string
+	get  func(pkgInfo *registry.PackageInfo) any
+}{
+	{"type", func(p *registry.PackageInfo) any { return p.Type }},
+	{"asset", func(p *registry.PackageInfo) any { return p.Asset }},
+	{"format", func(p *registry.PackageInfo) any { return p.Format }},
+	{"url", func(p *registry.PackageInfo) any { return p.URL }},
+	{"path", func(p *registry.PackageInfo) any { return p.Path }},
+	{"crate", func(p *registry.PackageInfo) any { return p.Crate }},
+	{"cargo", func(p *registry.PackageInfo) any { return p.Cargo }},
+	{"build", func(p *registry.PackageInfo) any { return p.Build }},
+	{"go_version_path", func(p *registry.PackageInfo) any { return p.GoVersionPath }},
+	{"files", func(p *registry.PackageInfo) any { return p.Files }},
+	{"replacements", func(p *registry.PackageInfo) any { return p.Replacements }},
+	{"format_overrides", func(p *registry.PackageInfo) any { return p.FormatOverrides }},
+	{"overrides", func(p *registry.PackageInfo) any { return p.Overrides }},
+	{"supported_envs", func(p *registry.PackageInfo) any { return p.SupportedEnvs }},
+	{"rosetta2", func(p *registry.PackageInfo) any { return p.Rosetta2 }},
+	{"windows_arm_emulation", func(p *registry.PackageInfo) any { return p.WindowsARMEmulation }},
+	{"no_asset", func(p *registry.PackageInfo) any { return p.NoAsset }},
+	{"complete_windows_ext", func(p *registry.PackageInfo) any { return p.CompleteWindowsExt }},
+	{"windows_ext", func(p *registry.PackageInfo) any { return p.WindowsExt }},
+	{"append_ext", func(p *registry.PackageInfo) any { return p.AppendExt }},
+	{"checksum", func(p *registry.PackageInfo) any { return p.Checksum }},
+	{"cosign", func(p *registry.PackageInfo) any { return p.Cosign }},
+	{"slsa_provenance", func(p *registry.PackageInfo) any { return p.SLSAProvenance }},
+	{"minisign", func(p *registry.PackageInfo) any { return p.Minisign }},
+	{"github_artifact_attestations", func(p *registry.PackageInfo) any { return p.GitHubArtifactAttestations }},
+	{"vars", func(p *registry.PackageInfo) any { return p.Vars }},
+	{"version_prefix", func(p *registry.PackageInfo) any { return p.VersionPrefix }},
+	{"version_filter", func(p *registry.PackageInfo) any { return p.VersionFilter }},
+	{"version_source", func(p *registry.PackageInfo) any { return p.VersionSource }},
+	{"version_constraint", func(p *registry.PackageInfo) any { return p.VersionConstraints }},
+	{"version_overrides", func(p *registry.PackageInfo) any { return p.VersionOverrides }},
+}
+
+// diffPackageInfo compares packages semantically and returns human-readable changes.
+// Values are compared as YAML, so the order of keys and comments are ignored.
+// If nothing meaningful is changed, it returns an empty list.
+func diffPackageInfo(oldPkg, newPkg *registry.PackageInfo) ([]string, error) {
+	changes := []string{}
+	for _, field := range scaffoldFields {
+		oldValue, err := marshalField(field.get(oldPkg))
+		if err != nil {
+			return nil, fmt.Errorf("marshal a field %s as YAML: %w", field.name, err)
+		}
+		newValue, err := marshalField(field.get(newPkg))
+		if err != nil {
+			return nil, fmt.Errorf("marshal a field %s as YAML: %w", field.name, err)
+		}
+		if oldValue == newValue {
+			continue
+		}
+		if strings.Contains(oldValue, "\n") || strings.Contains(newValue, "\n") {
+			changes = append(changes, fmt.Sprintf("`%s` was changed", field.name))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("`%s`: `%s` => `%s`", field.name, oldValue, newValue))
+	}
+	return changes, nil
+}
+
+func marshalField(v any) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	s := strings.TrimSuffix(string(b), "\n")
+	switch s {
+	case "", "null", `""`, "[]", "{}":
+		return "(none)", nil
+	}
+	return s, nil
+}
+
+// formatScaffoldChanges formats changes of registry.yaml as a Markdown list.
+func formatScaffoldChanges(changes []string) string {
+	b := &strings.Builder{}
+	for _, change := range changes {
+		b.WriteString("- " + change + "\n")
+	}
+	return b.String()
+}
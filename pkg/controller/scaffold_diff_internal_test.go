@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_diffPackageInfo(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name   string
+		oldPkg string
+		newPkg string
+		exp    []string
+	}{
+		{
+			name: "only order and comments are changed",
+			oldPkg: `type: github_release
+repo_owner: foo
+repo_name: bar
+# the asset
+asset: bar_{{.OS}}.tar.gz
+description: a tool
+supported_envs:
+  - darwin
+  - linux
+`,
+			newPkg: `type: github_release
+repo_owner: foo
+repo_name: bar
+description: A tool
+supported_envs:
+  - darwin
+  - linux
+asset: bar_{{.OS}}.tar.gz
+`,
+			exp: []string{},
+		},
+		{
+			name: "changed",
+			oldPkg: `type: github_release
+asset: bar_{{.OS}}.tar.gz
+supported_envs:
+  - darwin
+  - linux
+`,
+			newPkg: `type: github_release
+asset: bar_{{.OS}}_{{.Arch}}.tar.gz
+format: tar.gz
+supported_envs:
+  - darwin
+  - linux
+  - windows
+overrides:
+  - goos: windows
+    format: zip
+`,
+			exp: []string{
+				"`asset`: `bar_{{.OS}}.tar.gz` => `bar_{{.OS}}_{{.Arch}}.tar.gz`",
+				"`format`: `(none)` => `tar.gz`",
+				"`overrides` was changed",
+				"`supported_envs` was changed",
+			},
+		},
+		{
+			name: "fields about versions and windows are changed",
+			oldPkg: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar.tar.gz
+`,
+			newPkg: `type: github_release
+repo_owner: foo
+repo_name: bar
+asset: bar.tar.gz
+windows_ext: .sh
+version_prefix: bar/
+version_filter: not (Version contains "rc")
+version_source: github_tag
+`,
+			exp: []string{
+				"`windows_ext`: `(none)` => `.sh`",
+				"`version_prefix`: `(none)` => `bar/`",
+				"`version_filter`: `(none)` => `not (Version contains \"rc\")`",
+				"`version_source`: `(none)` => `github_tag`",
+			},
+		},
+		{
+			name: "vars, cargo, and build are changed",
+			oldPkg: `type: cargo
+repo_owner: foo
+repo_name: bar
+crate: bar
+`,
+			newPkg: `type: cargo
+repo_owner: foo
+repo_name: bar
+crate: bar
+cargo:
+  all_features: true
+build:
+  type: go_build
+  files:
+    - name: bar
+      src: ./cmd/bar
+go_version_path: go.mod
+vars:
+  - name: flavor
+    default: full
+`,
+			exp: []string{
+				"`cargo`: `(none)` => `all_features: true`",
+				"`build` was changed",
+				"`go_version_path`: `(none)` => `go.mod`",
+				"`vars` was changed",
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			oldPkg := &registry.PackageInfo{}
+			if err := yaml.Unmarshal([]byte(d.oldPkg), oldPkg); err != nil {
+				t.Fatal(err)
+			}
+			newPkg := &registry.PackageInfo{}
+			if err := yaml.Unmarshal([]byte(d.newPkg), newPkg); err != nil {
+				t.Fatal(err)
+			}
+			changes, err := diffPackageInfo(oldPkg, newPkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(changes, d.exp) {
+				t.Fatalf("wanted %v, got %v", d.exp, changes)
+			}
+		})
+	}
+}
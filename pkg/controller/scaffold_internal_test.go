@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+)
+
+func Test_scaffoldSkipReason(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name    string
+		oldType string
+		newType string
+		changes []string
+		skipped bool
+	}{
+		{
+			name:    "changed",
+			oldType: "github_release",
+			newType: "github_release",
+			changes: []string{"`format`: `tar.gz` => `zip`"},
+		},
+		{
+			name:    "not changed",
+			oldType: "cargo",
+			newType: "cargo",
+			changes: []string{},
+			skipped: true,
+		},
+		{
+			name:    "cargo is converted to github_release",
+			oldType: "cargo",
+			newType: "github_release",
+			changes: []string{"`type`: `cargo` => `github_release`"},
+			skipped: true,
+		},
+		{
+			name:    "github_release is converted to cargo",
+			oldType: "github_release",
+			newType: "cargo",
+			changes: []string{"`type`: `github_release` => `cargo`"},
+			skipped: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			reason := scaffoldSkipReason(&registry.PackageInfo{Type: d.oldType}, &registry.PackageInfo{Type: d.newType}, d.changes)
+			if (reason != "") != d.skipped {
+				t.Fatalf("wanted skipped=%v, got reason %q", d.skipped, reason)
+			}
+		})
+	}
+}
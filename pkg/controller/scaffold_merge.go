@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keepAnnotation is a comment to keep the field of registry.yaml when the package is re-scaffolded.
+//
+//	# aqua-registry-updater:keep
+//	description: hand-written description
+const keepAnnotation = "aqua-registry-updater:keep"
+
+// mergeManualFields overlays fields of the old registry.yaml which are written by hand on the regenerated registry.yaml.
+// Fields in keepFields and fields annotated with keepAnnotation, including fields whose nested fields are annotated, are kept.
+// It returns the merged registry.yaml and the names of kept fields.
+// If no field is kept, newBody is returned as is.
+func mergeManualFields(oldBody, newBody []byte, keepFields []string) ([]byte, []string, error) {
+	_, oldPkg, err := parseRegistryNode(oldBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, newPkg, err := parseRegistryNode(newBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept := []string{}
+	for i := 0; i+1 < len(oldPkg.Content); i += 2 {
+		key, value := oldPkg.Content[i], oldPkg.Content[i+1]
+		if !slices.Contains(keepFields, key.Value) && !hasKeepAnnotation(key, value) {
+			continue
+		}
+		kept = append(kept, key.Value)
+		setMappingValue(newPkg, key, value)
+	}
+	if len(kept) == 0 {
+		return newBody, kept, nil
+	}
+	b, err := encodeYAMLNode(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, kept, nil
+}
+
+// hasKeepAnnotation returns true if the field or any of its descendants is annotated with keepAnnotation.
+// The whole top-level field is kept even if only a nested field such as checksum.asset is annotated.
+func hasKeepAnnotation(key, value *yaml.Node) bool {
+	if isKeepComment(key.HeadComment) || isKeepComment(key.LineComment) {
+		return true
+	}
+	return hasKeepComment(value)
+}
+
+func hasKeepComment(node *yaml.Node) bool {
+	if isKeepComment(node.HeadComment) || isKeepComment(node.LineComment) || isKeepComment(node.FootComment) {
+		return true
+	}
+	return slices.ContainsFunc(node.Content, hasKeepComment)
+}
+
+func isKeepComment(comment string) bool {
+	for line := range strings.SplitSeq(comment, "\n") {
+		if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")) == keepAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// setMappingValue replaces the value of the key in the mapping node.
+// If the key isn't found, the key and the value are appended.
+func setMappingValue(node, key, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key.Value {
+			node.Content[i] = key
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, key, value)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_mergeManualFields(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		name       string
+		oldBody    string
+		newBody    string
+		keepFields []string
+		exp        string
+		kept       []string
+	}{
+		{
+			name: "nothing is kept",
+			oldBody: `packages:
+  - type: github_release
+    repo_owner: foo
+    repo_name: bar
+    description: hand-written
+`,
+			newBody: `packages:
+  - type:   github_release
+    repo_owner: foo
+    repo_name: bar
+    description: generated
+`,
+			exp: `packages:
+  - type:   github_release
+    repo_owner: foo
+    repo_name: bar
+    description: generated
+`,
+			kept: []string{},
+		},
+		{
+			name: "keep fields",
+			oldBody: `packages:
+  - type: github_release
+    repo_owner: foo
+    repo_name: bar
+    # aqua-registry-updater:keep
+    description: hand-written
+    asset: bar_{{.OS}}.tar.gz
+    files:
+      - name: bar
+        src: bar_{{.OS}}/bar
+    checksum:
+      type: github_release
+      asset: checksums.txt # aqua-registry-updater:keep
+`,
+			newBody: `# yaml-language-server: $schema=https://raw.githubusercontent.com/aquaproj/aqua/main/json-schema/registry.json
+packages:
+  - type: github_release
+    repo_owner: foo
+    repo_name: bar
+    description: generated
+    asset: bar_{{.OS}}_{{.Arch}}.tar.gz
+`,
+			keepFields: []string{"files"},
+			exp: `# yaml-language-server: $schema=https://raw.githubusercontent.com/aquaproj/aqua/main/json-schema/registry.json
+packages:
+  - type: github_release
+    repo_owner: foo
+    repo_name: bar
+    # aqua-registry-updater:keep
+    description: hand-written
+    asset: bar_{{.OS}}_{{.Arch}}.tar.gz
+    files:
+      - name: bar
+        src: bar_{{.OS}}/bar
+    checksum:
+      type: github_release
+      asset: checksums.txt # aqua-registry-updater:keep
+`,
+			kept: []string{"description", "files", "checksum"},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			b, kept, err := mergeManualFields([]byte(d.oldBody), []byte(d.newBody), d.keepFields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(kept, d.kept) {
+				t.Fatalf("wanted %v, got %v", d.kept, kept)
+			}
+			if string(b) != d.exp {
+				t.Fatalf("wanted:\n%s\ngot:\n%s", d.exp, string(b))
+			}
+		})
+	}
+}
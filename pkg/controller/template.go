@@ -12,6 +12,11 @@ type Templates struct {
 	TransferPRBody  string `yaml:"transfer_pr_body"`
 	ScaffoldPRTitle string `yaml:"scaffold_pr_title"`
 	ScaffoldPRBody  string `yaml:"scaffold_pr_body"`
+	ArchivedPRTitle string `yaml:"archived_pr_title"`
+	ArchivedPRBody  string `yaml:"archived_pr_body"`
+
+	RepositoryGoneIssueTitle string `yaml:"repository_gone_issue_title"`
+	RepositoryGoneIssueBody  string `yaml:"repository_gone_issue_body"`
 }
 
 type CompiledTemplates struct {
@@ -21,9 +26,16 @@ type CompiledTemplates struct {
 	TransferPRBody  *template.Template
 	ScaffoldPRTitle *template.Template
 	ScaffoldPRBody  *template.Template
+	ArchivedPRTitle *template.Template
+	ArchivedPRBody  *template.Template
+
+	RepositoryGoneIssueTitle *template.Template
+	RepositoryGoneIssueBody  *template.Template
 }
 
 type ParamTemplates struct {
+	// ServerURL is the URL of GitHub such as https://github.com.
+	ServerURL      string
 	PackageName    string
 	RepoOwner      string
 	RepoName       string
@@ -34,6 +46,22 @@ type ParamTemplates struct {
 	NewRepoOwner   string
 	NewRepoName    string
 	NewPackageName string
+	// RegistryRepoOwner and RegistryRepoName are the repository of the registry where pull requests are created.
+	RegistryRepoOwner string
+	RegistryRepoName  string
+	// BaseBranch is the base branch of the pull request.
+	BaseBranch string
+	// Alias is the old package name added to aliases of the transferred package.
+	// It's empty if no alias is added.
+	Alias string
+	// ScaffoldChanges is the list of meaningful changes of registry.yaml by re-scaffolding as Markdown.
+	ScaffoldChanges string
+	// Duplicated is true if the new package already existed, so the old package is removed instead of being moved.
+	Duplicated bool
+	// Releases are releases newer than CurrentVersion and older than or equal to NewVersion.
+	Releases []*Release
+	// ReleaseNotes is the release notes of Releases formatted as collapsible blocks.
+	ReleaseNotes string
 }
 
 func compileTemplate(s string) (*template.Template, error) {
@@ -7,10 +7,11 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aquaproj/aqua/v2/pkg/versiongetter"
 	"github.com/spf13/afero"
@@ -75,13 +76,52 @@ func (c *Controller) Update(ctx context.Context, logger *slog.Logger, param *Par
 		})
 	}
 
+	c.param.RateLimit.setMaxRetryAfter(cfg.RateLimit.MaxRetryAfter)
+	if cfg.RateLimit.Check {
+		if err := c.checkRateLimit(ctx); err != nil {
+			slogerr.WithError(logger, err).Warn("check rate limits")
+		}
+	}
+
+	logger.Info("listing open pull requests created by aqua-registry-updater")
+	openPRs, err := c.listOpenPRs(ctx)
+	if err != nil {
+		return err
+	}
+	state := &runState{
+		openPRs: openPRs,
+		data:    data,
+	}
+
+	logger.Info("closing obsolete pull requests")
+	c.reconcile(ctx, logger, state)
+
+	if cfg.BranchCleanup.IsEnabled() {
+		logger.Info("deleting stale branches")
+		if err := c.cleanupBranches(ctx, logger, cfg.BranchCleanup, state); err != nil {
+			slogerr.WithError(logger, err).Error("delete stale branches")
+		}
+	}
+
 	if len(param.Args) != 0 {
-		return c.handleArgs(ctx, logger, param, data, repo, tag, cfg, ignorePkgsM)
+		return c.handleArgs(ctx, logger, param, data, repo, tag, cfg, ignorePkgsM, state)
 	}
 
 	var idx int
+	cnt := 0
 	defer func() { //nolint:contextcheck
 		data.Packages = append(data.Packages[idx:], data.Packages[:idx]...)
+		if cfg.Dashboard.IsEnabled() {
+			logger.Info("updating the dashboard issue")
+			if err := c.updateDashboard(context.Background(), logger, cfg.Dashboard, &ParamDashboard{
+				Data:           data,
+				OpenPRs:        state.openPRs,
+				IgnorePackages: cfg.IgnorePackages,
+				Handled:        cnt,
+			}); err != nil {
+				slogerr.WithError(logger, err).Error("update the dashboard issue")
+			}
+		}
 		if err := c.writeData("data.json", data); err != nil {
 			slogerr.WithError(logger, err).Error("update data.json")
 			return
@@ -91,10 +131,12 @@ func (c *Controller) Update(ctx context.Context, logger *slog.Logger, param *Par
 			slogerr.WithError(logger, err).Error("push data.json to the container registry")
 		}
 	}()
-	cnt := 0
 	for i, pkg := range data.Packages {
 		idx = i
-		if cnt == cfg.Limit { // Limitation to avoid GitHub API rate limiting
+		if cnt == cfg.Limit {
+			break
+		}
+		if c.shouldStop(logger, cfg, state) {
 			break
 		}
 		if _, ok := ignorePkgsM[pkg.Name]; ok {
@@ -102,10 +144,19 @@ func (c *Controller) Update(ctx context.Context, logger *slog.Logger, param *Par
 		}
 		logger := logger.With("pkg_name", pkg.Name)
 		logger.Info("handling a package")
-		incremented, err := c.handlePackage(ctx, logger, pkg, cfg)
+		incremented, err := c.handlePackage(ctx, logger, pkg, cfg, state)
+		if err != nil && isRateLimitError(err) {
+			// The package isn't regarded as failing and is handled first in the next run.
+			slogerr.WithError(logger, err).Warn("stop handling packages because GitHub API rate limit was hit")
+			if err := goexec.Command(ctx, "git", "checkout", "--", ".").Run(); err != nil {
+				slogerr.WithError(logger, err).Error("clear changes by git checkout")
+			}
+			break
+		}
 		if err != nil {
 			slogerr.WithError(logger, err).Error("handle a package")
 		}
+		pkg.setResult(err)
 		if err := goexec.Command(ctx, "git", "checkout", "--", ".").Run(); err != nil {
 			slogerr.WithError(logger, err).Error("clear changes by git checkout")
 		}
@@ -117,7 +168,21 @@ func (c *Controller) Update(ctx context.Context, logger *slog.Logger, param *Par
 	return nil
 }
 
-func (c *Controller) handleArgs(ctx context.Context, logger *slog.Logger, param *Param, data *Data, repo *remote.Repository, tag string, cfg *Config, ignorePkgsM map[string]struct{}) error {
+// shouldStop returns true if no more packages should be handled because of max_open_prs or GitHub API rate limits.
+// max_automerge_per_day is checked in handlePackage because pull requests are still created after it's reached.
+func (c *Controller) shouldStop(logger *slog.Logger, cfg *Config, state *runState) bool {
+	if cfg.MaxOpenPRs > 0 && len(state.openPRs) >= cfg.MaxOpenPRs {
+		logger.Info("stop handling packages because the number of open pull requests reaches max_open_prs", "max_open_prs", cfg.MaxOpenPRs)
+		return true
+	}
+	if c.isRateLimitExceeded(logger, cfg.RateLimit) {
+		logger.Warn("stop handling packages to avoid exhausting GitHub API rate limits")
+		return true
+	}
+	return false
+}
+
+func (c *Controller) handleArgs(ctx context.Context, logger *slog.Logger, param *Param, data *Data, repo *remote.Repository, tag string, cfg *Config, ignorePkgsM map[string]struct{}, state *runState) error {
 	defer func() { //nolint:contextcheck
 		if err := c.writeData("data.json", data); err != nil {
 			slogerr.WithError(logger, err).Error("update data.json")
@@ -129,6 +194,9 @@ func (c *Controller) handleArgs(ctx context.Context, logger *slog.Logger, param
 		}
 	}()
 	for _, arg := range param.Args {
+		if c.shouldStop(logger, cfg, state) {
+			return nil
+		}
 		for i, pkg := range data.Packages {
 			if pkg.Name != arg {
 				continue
@@ -139,9 +207,11 @@ func (c *Controller) handleArgs(ctx context.Context, logger *slog.Logger, param
 			}
 			logger := logger.With("pkg_name", pkg.Name)
 			logger.Info("handling a package")
-			if _, err := c.handlePackage(ctx, logger, pkg, cfg); err != nil {
+			_, err := c.handlePackage(ctx, logger, pkg, cfg, state)
+			if err != nil {
 				slogerr.WithError(logger, err).Error("handle a package")
 			}
+			pkg.setResult(err)
 		}
 	}
 	return nil
@@ -164,16 +234,23 @@ func (c *Controller) listPkgYAML() ([]string, error) {
 	return pkgPaths, nil
 }
 
-func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config) (bool, error) { //nolint:cyclop,funlen
-	redirected, err := c.fixRedirect(ctx, logger, pkg, cfg)
+func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg *Package, cfg *Config, state *runState) (bool, error) { //nolint:cyclop,funlen
+	redirected, err := c.fixRedirect(ctx, logger, pkg, cfg, state)
 	if err != nil {
 		return false, err
 	}
 	if redirected {
 		return true, nil
 	}
+	handled, err := c.checkUpstream(ctx, logger, pkg, cfg, state)
+	if err != nil {
+		return false, err
+	}
+	if handled {
+		return true, nil
+	}
 	if cfg.Scaffold.IsEnabled() {
-		scaffolded, err := c.scaffold(ctx, logger, pkg, cfg)
+		scaffolded, err := c.scaffold(ctx, logger, pkg, cfg, state)
 		if err != nil {
 			return false, err
 		}
@@ -205,6 +282,7 @@ func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg
 	if err != nil {
 		return true, fmt.Errorf("update pkg.yaml: %w", err)
 	}
+	pkg.SkippedVersion = ""
 	if newVersion == "" {
 		return true, nil
 	}
@@ -224,17 +302,31 @@ func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg
 		slogerr.WithError(logger, err).Warn("compare version")
 	} else if !automerged {
 		slogerr.WithError(logger, err).Warn("ignore the change")
+		if isPrefixChanged(currentVersion, newVersion) {
+			pkg.SkippedVersion = newVersion
+		}
 		return true, nil
 	}
 
+	// Check the existing pull request before calling GitHub API to get release notes and assets.
+	branch := fmt.Sprintf("%s%s-%s", branchPrefix, pkg.Name, newVersion)
+	prs := c.updatePRs(state, pkg.Name)
+	for _, pr := range prs {
+		if pr.GetHead().GetRef() == branch {
+			logger.Info("a pull request already exists", "pr_number", pr.GetNumber())
+			return true, nil
+		}
+	}
+
 	paramTemplates := &ParamTemplates{
+		ServerURL:      c.param.URLs.ServerURL,
 		PackageName:    pkg.Name,
 		RepoOwner:      repoOwner,
 		RepoName:       repoName,
 		NewVersion:     newVersion,
 		CurrentVersion: currentVersion,
-		CompareURL:     fmt.Sprintf(`https://github.com/%s/%s/compare/%s...%s`, repoOwner, repoName, currentVersion, newVersion),
-		ReleaseURL:     fmt.Sprintf(`https://github.com/%s/%s/releases/tag/%s`, repoOwner, repoName, newVersion),
+		CompareURL:     fmt.Sprintf(`%s/%s/%s/compare/%s...%s`, c.param.URLs.ServerURL, repoOwner, repoName, currentVersion, newVersion),
+		ReleaseURL:     fmt.Sprintf(`%s/%s/%s/releases/tag/%s`, c.param.URLs.ServerURL, repoOwner, repoName, newVersion),
 	}
 
 	if strings.Contains(repoOwner, ".") {
@@ -242,6 +334,41 @@ func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg
 		paramTemplates.RepoName = ""
 		paramTemplates.CompareURL = ""
 		paramTemplates.ReleaseURL = ""
+	} else {
+		releases, err := c.listReleases(ctx, repoOwner, repoName, currentVersion, newVersion)
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("get release notes")
+		}
+		paramTemplates.Releases = releases
+		paramTemplates.ReleaseNotes = formatReleaseNotes(releases, maxReleaseNotesLength)
+	}
+
+	warnings := []string{}
+	if tags := cfg.BreakingChange.findBreakingChanges(paramTemplates.Releases); len(tags) != 0 {
+		logger.Info("auto-merge is disabled because release notes may include breaking changes", "releases", tags)
+		automerged = false
+		warnings = append(warnings, fmt.Sprintf("Auto-merge is disabled because release notes of %s may include breaking changes.", strings.Join(tags, ", ")))
+	}
+	pkgInfo, err := c.readPackageInfo(pkg.Name)
+	if err != nil {
+		slogerr.WithError(logger, err).Warn("read registry.yaml")
+	}
+	if pkgInfo != nil {
+		if warning := checkVersionOverride(logger, pkgInfo, currentVersion, newVersion); warning != "" {
+			logger.Info("auto-merge is disabled because the matching version_overrides changes")
+			automerged = false
+			warnings = append(warnings, warning)
+		}
+	}
+	if pkgInfo != nil && !strings.Contains(repoOwner, ".") {
+		missing, err := c.checkAssetCoverage(ctx, logger, pkgInfo, newVersion)
+		if err != nil {
+			slogerr.WithError(logger, err).Warn("check assets of the new release")
+		} else if len(missing) != 0 {
+			logger.Info("auto-merge is disabled because assets of some platforms aren't found", "missing_assets", formatMissingAssets(missing))
+			automerged = false
+			warnings = append(warnings, fmt.Sprintf("Auto-merge is disabled because assets of the following platforms aren't found in the release %s: %s. Assets may have been renamed or platforms may have been dropped.", newVersion, formatMissingAssets(missing)))
+		}
 	}
 
 	prTitle, err := renderTemplate(cfg.compiledTemplates.PRTitle, paramTemplates)
@@ -253,26 +380,50 @@ func (c *Controller) handlePackage(ctx context.Context, logger *slog.Logger, pkg
 	if err != nil {
 		return true, fmt.Errorf("render a template pr_body: %w", err)
 	}
+	prBody = truncate(formatWarnings(warnings)+prBody, maxPRBodyLength)
 
-	branch := fmt.Sprintf("aqua-registry-updater-%s-%s", pkg.Name, newVersion)
-	if err := c.exec(ctx, "ghcp", "commit", "-r", fmt.Sprintf("%s/%s", c.param.RepoOwner, c.param.RepoName), "-b", branch, "-m", prTitle, pkgPath); err != nil {
+	prCfg := cfg.PullRequest.ForPackage(pkg.Name)
+	if _, err := c.commit(ctx, &ParamCommit{
+		Base:    prCfg.Base,
+		Branch:  branch,
+		Message: prTitle,
+		Files:   []string{pkgPath},
+	}); err != nil {
+		if errors.Is(err, errNothingToCommit) {
+			logger.Info("pkg.yaml isn't changed from the base branch, so a pull request isn't created")
+			return true, nil
+		}
 		return true, fmt.Errorf("create a branch: %w", err)
 	}
-	prNumber, err := c.createPR(ctx, &ParamCreatePR{
+	pr, err := c.createPR(ctx, logger, &ParamCreatePR{
 		NewVersion:     newVersion,
 		CurrentVersion: currentVersion,
 		Title:          prTitle,
 		Branch:         branch,
 		Body:           prBody,
+		Config:         prCfg,
 	})
 	if err != nil {
 		return true, fmt.Errorf("create a pull request: %w", err)
 	}
+	state.openPRs = append(state.openPRs, pr)
+	c.closeSupersededPRs(ctx, logger, state, prs, pr)
+
+	if automerged && cfg.MaxAutoMergePerDay > 0 && state.data.countAutoMerges(time.Now()) >= cfg.MaxAutoMergePerDay {
+		logger.Info("auto-merge isn't enabled because the number of auto-merged pull requests reaches max_automerge_per_day", "max_automerge_per_day", cfg.MaxAutoMergePerDay)
+		return true, nil
+	}
+
+	if automerged && prCfg.Draft {
+		logger.Info("auto-merge isn't enabled because the pull request is a draft")
+		return true, nil
+	}
 
 	if automerged {
-		if err := c.exec(ctx, "gh", "-R", fmt.Sprintf("%s/%s", c.param.RepoOwner, c.param.RepoName), "pr", "merge", "-s", "--auto", strconv.Itoa(prNumber)); err != nil {
+		if err := c.gql.EnablePullRequestAutoMerge(ctx, pr.GetNodeID(), cfg.AutoMerge.MergeMethod); err != nil {
 			return true, fmt.Errorf("enable auto-merge: %w", err)
 		}
+		state.data.AutoMerges = append(state.data.AutoMerges, time.Now())
 	}
 	return true, nil
 }
@@ -301,6 +452,19 @@ func compareVersion(currentVersion, newVersion string) (bool, error) {
 	return nv.GreaterThan(cv), nil
 }
 
+// isPrefixChanged returns true if the prefix of the new version is different from the current version.
+func isPrefixChanged(currentVersion, newVersion string) bool {
+	_, cvPrefix, err := versiongetter.GetVersionAndPrefix(currentVersion)
+	if err != nil {
+		return false
+	}
+	_, nvPrefix, err := versiongetter.GetVersionAndPrefix(newVersion)
+	if err != nil {
+		return false
+	}
+	return cvPrefix != nvPrefix
+}
+
 func (c *Controller) getCurrentVersion(pkgName, content string) (string, error) {
 	pattern, err := regexp.Compile(fmt.Sprintf(`- name: %s@(.*)`, pkgName))
 	if err != nil {
@@ -350,7 +514,12 @@ func (c *Controller) updatePkgYAML(ctx context.Context, pkgName, pkgPath, conten
 }
 
 func (c *Controller) aquaGenerate(ctx context.Context, pkgName string) (string, error) {
+	env, err := c.commandEnv()
+	if err != nil {
+		return "", err
+	}
 	cmd := goexec.Command(ctx, "aqua", "g", pkgName)
+	cmd.Env = env
 	buf := &bytes.Buffer{}
 	cmd.Stdout = buf
 	cmd.Stderr = c.stderr
@@ -360,6 +529,19 @@ func (c *Controller) aquaGenerate(ctx context.Context, pkgName string) (string,
 	return strings.TrimSpace(buf.String()), nil
 }
 
+// commandEnv returns environment variables of commands calling GitHub API such as aqua.
+// If the token source is set, GITHUB_TOKEN is overwritten so that commands call GitHub API as the same identity as aqua-registry-updater.
+func (c *Controller) commandEnv() ([]string, error) {
+	if c.param.TokenSource == nil {
+		return nil, nil
+	}
+	token, err := c.param.TokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("get a GitHub Access Token: %w", err)
+	}
+	return append(os.Environ(), "GITHUB_TOKEN="+token.AccessToken), nil
+}
+
 func (c *Controller) exec(ctx context.Context, command string, args ...string) error {
 	cmd := goexec.Command(ctx, command, args...)
 	cmd.Stdout = c.stdout
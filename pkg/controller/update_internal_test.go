@@ -1,7 +1,11 @@
 package controller
 
 import (
+	"io"
+	"log/slog"
 	"testing"
+
+	"github.com/google/go-github/v89/github"
 )
 
 func Test_compareVersion(t *testing.T) { //nolint:funlen
@@ -73,3 +77,63 @@ func Test_compareVersion(t *testing.T) { //nolint:funlen
 		})
 	}
 }
+
+func TestController_shouldStop(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		name       string
+		maxOpenPRs int
+		openPRs    int
+		remaining  int
+		exp        bool
+	}{
+		{
+			name:       "continue",
+			maxOpenPRs: 3,
+			openPRs:    2,
+			remaining:  1000,
+		},
+		{
+			name:       "max_open_prs",
+			maxOpenPRs: 3,
+			openPRs:    3,
+			remaining:  1000,
+			exp:        true,
+		},
+		{
+			name:      "max_open_prs isn't set",
+			openPRs:   3,
+			remaining: 1000,
+		},
+		{
+			name:       "rate limit",
+			maxOpenPRs: 3,
+			remaining:  10,
+			exp:        true,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			rateLimit := NewRateLimit()
+			rateLimit.set("core", d.remaining)
+			ctrl := &Controller{
+				param: &ParamNew{RateLimit: rateLimit},
+			}
+			cfg := &Config{
+				MaxOpenPRs: d.maxOpenPRs,
+				RateLimit: &RateLimitConfig{
+					CoreReserve:    new(defaultCoreReserve),
+					GraphQLReserve: new(defaultGraphQLReserve),
+				},
+			}
+			state := &runState{
+				openPRs: make([]*github.PullRequest, d.openPRs),
+			}
+			if got := ctrl.shouldStop(logger, cfg, state); got != d.exp {
+				t.Fatalf("wanted %v, got %v", d.exp, got)
+			}
+		})
+	}
+}
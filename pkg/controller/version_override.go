@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"github.com/aquaproj/aqua/v2/pkg/expr"
+	"github.com/suzuki-shunsuke/slog-error/slogerr"
+)
+
+const (
+	// versionOverrideTop means the top-level configuration matches the version.
+	versionOverrideTop = -1
+	// versionOverrideNone means neither the top-level configuration nor version_overrides match the version.
+	// aqua falls back to the top-level configuration, but the package is likely broken.
+	versionOverrideNone = -2
+)
+
+// matchVersionOverride returns the index of version_overrides which matches the version in the same way as aqua.
+// If the top-level version_constraint matches the version or version_constraint isn't set, it returns versionOverrideTop.
+// If nothing matches, it returns versionOverrideNone.
+func matchVersionOverride(logger *slog.Logger, pkgInfo *registry.PackageInfo, version string) int {
+	if pkgInfo.VersionConstraints == "" {
+		return versionOverrideTop
+	}
+	if evaluateVersionConstraint(logger, pkgInfo.VersionConstraints, pkgInfo.VersionPrefix, version) {
+		return versionOverrideTop
+	}
+	for i, vo := range pkgInfo.VersionOverrides {
+		prefix := pkgInfo.VersionPrefix
+		if vo.VersionPrefix != nil {
+			prefix = *vo.VersionPrefix
+		}
+		if evaluateVersionConstraint(logger, vo.VersionConstraints, prefix, version) {
+			return i
+		}
+	}
+	return versionOverrideNone
+}
+
+func evaluateVersionConstraint(logger *slog.Logger, constraint, prefix, version string) bool {
+	semver := version
+	if prefix != "" {
+		s, ok := strings.CutPrefix(version, prefix)
+		if !ok {
+			return false
+		}
+		semver = s
+	}
+	matched, err := expr.EvaluateVersionConstraints(logger, constraint, version, semver)
+	if err != nil {
+		// aqua treats version_constraint as false if it fails to evaluate it.
+		slogerr.WithError(logger, err).Debug("evaluate the version_constraint", "version_constraint", constraint)
+		return false
+	}
+	return matched
+}
+
+func describeVersionOverride(pkgInfo *registry.PackageInfo, idx int) string {
+	switch idx {
+	case versionOverrideTop:
+		return "the top-level configuration"
+	case versionOverrideNone:
+		return "no configuration"
+	}
+	return fmt.Sprintf("version_overrides[%d] (`%s`)", idx, pkgInfo.VersionOverrides[idx].VersionConstraints)
+}
+
+// checkVersionOverride returns a warning if the configuration of registry.yaml used for the new version is different from the current version.
+// If the configuration isn't changed, it returns an empty string.
+func checkVersionOverride(logger *slog.Logger, pkgInfo *registry.PackageInfo, currentVersion, newVersion string) string {
+	cur := matchVersionOverride(logger, pkgInfo, currentVersion)
+	next := matchVersionOverride(logger, pkgInfo, newVersion)
+	if cur == next {
+		return ""
+	}
+	return fmt.Sprintf("Auto-merge is disabled because the configuration of registry.yaml matching the version changes from %s for %s to %s for %s. Please confirm the configuration is correct.",
+		describeVersionOverride(pkgInfo, cur), currentVersion, describeVersionOverride(pkgInfo, next), newVersion)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aquaproj/aqua/v2/pkg/config/registry"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_matchVersionOverride(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	pkgInfo := `type: github_release
+repo_owner: foo
+repo_name: bar
+version_prefix: bar-
+version_constraint: "false"
+version_overrides:
+  - version_constraint: semver("< 1.0.0")
+    asset: bar.tar.gz
+  - version_constraint: semver("< 2.0.0")
+    asset: bar_{{.OS}}.tar.gz
+`
+	data := []struct {
+		name    string
+		pkgInfo string
+		version string
+		exp     int
+	}{
+		{
+			name:    "no version_constraint",
+			pkgInfo: "type: github_release\n",
+			version: "v1.0.0",
+			exp:     versionOverrideTop,
+		},
+		{
+			name:    "first override",
+			pkgInfo: pkgInfo,
+			version: "bar-0.9.0",
+			exp:     0,
+		},
+		{
+			name:    "second override",
+			pkgInfo: pkgInfo,
+			version: "bar-1.5.0",
+			exp:     1,
+		},
+		{
+			name:    "nothing matches",
+			pkgInfo: pkgInfo,
+			version: "bar-2.0.0",
+			exp:     versionOverrideNone,
+		},
+		{
+			name:    "prefix doesn't match",
+			pkgInfo: pkgInfo,
+			version: "0.9.0",
+			exp:     versionOverrideNone,
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			t.Parallel()
+			p := &registry.PackageInfo{}
+			if err := yaml.Unmarshal([]byte(d.pkgInfo), p); err != nil {
+				t.Fatal(err)
+			}
+			if got := matchVersionOverride(logger, p, d.version); got != d.exp {
+				t.Fatalf("wanted %d, got %d", d.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseRegistryNode parses registry.yaml of a package as a YAML node to edit it with keeping comments.
+// It returns the document node and the mapping node of the package.
+func parseRegistryNode(body []byte) (*yaml.Node, *yaml.Node, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(body, doc); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal registry.yaml as YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil, errors.New("registry.yaml is empty")
+	}
+	pkgs := mappingValue(doc.Content[0], "packages")
+	if pkgs == nil || pkgs.Kind != yaml.SequenceNode || len(pkgs.Content) != 1 {
+		return nil, nil, errors.New("registry.yaml must have only one package")
+	}
+	pkg := pkgs.Content[0]
+	if pkg.Kind != yaml.MappingNode {
+		return nil, nil, errors.New("package must be a map")
+	}
+	return doc, pkg, nil
+}
+
+func encodeYAMLNode(doc *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2) //nolint:mnd
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("marshal registry.yaml as YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("marshal registry.yaml as YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value of the key in the mapping node.
+// If the node isn't a mapping or the key isn't found, it returns nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}